@@ -0,0 +1,7 @@
+data "ansible-forms_categories_data_source" "categories" {
+  cx_profile_name = "cluster1"
+}
+
+output "form_categories" {
+  value = { for category in data.ansible-forms_categories_data_source.categories.categories : category.name => category.form_count }
+}
@@ -0,0 +1,4 @@
+data "ansible-forms_form_data_source" "form" {
+  cx_profile_name = "cluster1"
+  name            = "Demo Form Ansible No input"
+}
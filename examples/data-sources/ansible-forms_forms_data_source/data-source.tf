@@ -0,0 +1,4 @@
+data "ansible-forms_forms_data_source" "storage_forms" {
+  cx_profile_name = "cluster1"
+  category        = "Storage"
+}
@@ -0,0 +1,10 @@
+data "ansible-forms_job_by_correlation_data_source" "provisioning" {
+  cx_profile_name = "cluster1"
+  form_name       = "Demo Form Ansible No input"
+  extravar_name   = "correlation_id"
+  extravar_value  = "change-4711"
+}
+
+output "provisioning_job" {
+  value = "job ${data.ansible-forms_job_by_correlation_data_source.provisioning.id} is ${data.ansible-forms_job_by_correlation_data_source.provisioning.status}"
+}
@@ -0,0 +1,9 @@
+data "ansible-forms_job_output_data_source" "nightly" {
+  cx_profile_name = "cluster1"
+  job_id          = 42
+  tail_lines      = 50
+}
+
+output "nightly_job_log" {
+  value = data.ansible-forms_job_output_data_source.nightly.complete ? data.ansible-forms_job_output_data_source.nightly.output : "job ${data.ansible-forms_job_output_data_source.nightly.status}"
+}
@@ -0,0 +1,6 @@
+data "ansible-forms_jobs_data_source" "failed_jobs" {
+  cx_profile_name = "cluster1"
+  status          = "failed"
+  form_name       = "Demo Form Ansible No input"
+  limit           = 10
+}
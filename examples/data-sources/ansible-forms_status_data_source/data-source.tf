@@ -0,0 +1,10 @@
+data "ansible-forms_status_data_source" "preflight" {
+  cx_profile_name = "cluster1"
+
+  lifecycle {
+    postcondition {
+      condition     = self.authenticated
+      error_message = "Ansible Forms rejected the credentials of cluster1."
+    }
+  }
+}
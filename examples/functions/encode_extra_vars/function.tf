@@ -0,0 +1,9 @@
+# Provider functions require Terraform 1.8 or later
+output "share_extra_vars" {
+  value = provider::ansible-forms::encode_extra_vars({
+    share_name = "myshare_name"
+    size       = 10
+    protocols  = ["nfs", "smb"]
+    quota      = { enabled = true, limit_gb = 500 }
+  })
+}
@@ -0,0 +1,4 @@
+# Provider functions require Terraform 1.8 or later
+output "job_succeeded" {
+  value = provider::ansible-forms::jobstatus_is_success(ansible-forms_job_resource.job.status)
+}
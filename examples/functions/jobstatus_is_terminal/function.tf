@@ -0,0 +1,4 @@
+# Provider functions require Terraform 1.8 or later
+output "job_done" {
+  value = provider::ansible-forms::jobstatus_is_terminal(ansible-forms_job_resource.job.status)
+}
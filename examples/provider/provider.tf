@@ -15,7 +15,17 @@ provider "ansible-forms" {
       password       = var.password
       hostname       = "127.0.0.1:8443" # Publicly available by Ansible Forms
       validate_certs = var.validate_certs
+    },
+    {
+      # a read-only account for data sources, resources keep using cluster1
+      name           = "cluster1_readonly"
+      username       = var.readonly_username
+      password       = var.readonly_password
+      hostname       = "127.0.0.1:8443"
+      validate_certs = var.validate_certs
     }
   ]
+  default_connection_profile     = "cluster1"
+  data_source_connection_profile = "cluster1_readonly"
 }
 
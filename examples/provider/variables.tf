@@ -6,6 +6,13 @@ variable "password" {
   type      = string
   sensitive = true
 }
+variable "readonly_username" {
+  type = string
+}
+variable "readonly_password" {
+  type      = string
+  sensitive = true
+}
 variable "hostname" {
   type      = string
   sensitive = true
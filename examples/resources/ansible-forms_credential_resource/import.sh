@@ -0,0 +1,2 @@
+# A credential is imported with the connection profile name and the credential id
+terraform import ansible-forms_credential_resource.ontap cluster1,12
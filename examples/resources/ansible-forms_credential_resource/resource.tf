@@ -0,0 +1,7 @@
+resource "ansible-forms_credential_resource" "ontap" {
+  cx_profile_name = "cluster1"
+  name            = "ontap_cred"
+  username        = "admin"
+  password        = var.ontap_password
+  description     = "ONTAP cluster administrator"
+}
@@ -0,0 +1,2 @@
+# A group is imported with the connection profile name and the group id
+terraform import ansible-forms_group_resource.operators cluster1,3
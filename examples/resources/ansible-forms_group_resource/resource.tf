@@ -0,0 +1,6 @@
+resource "ansible-forms_group_resource" "operators" {
+  cx_profile_name = "cluster1"
+  name            = "operators"
+  role            = "operator"
+  members         = ["alice", "bob"]
+}
@@ -0,0 +1,13 @@
+resource "ansible-forms_job_batch_resource" "jobs" {
+  cx_profile_name = "cluster1"
+  form_name       = "Demo Form Ansible No input"
+  targets         = ["host1", "host2", "host3"]
+  extravars = {
+    region = "myregion"
+    env    = "myenv"
+  }
+}
+
+output "ansible-forms_job_batch_resource" {
+  value = ansible-forms_job_batch_resource.jobs.jobs
+}
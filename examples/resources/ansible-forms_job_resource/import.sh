@@ -0,0 +1,3 @@
+# A job is imported with its id, or with the connection profile name and the job id
+terraform import ansible-forms_job_resource.job 42
+terraform import ansible-forms_job_resource.job cluster1,42
@@ -1,6 +1,7 @@
 resource "ansible-forms_job_resource" "job" {
   cx_profile_name = "cluster1"
   form_name       = "Demo Form Ansible No input"
+  validate_inputs = true
   extravars = {
     name                = "github.com/dsha256"
     region              = "myregion"
@@ -18,6 +19,10 @@ resource "ansible-forms_job_resource" "job" {
     ontap_cred = "myontap_cred"
     bind_cred  = "mybind_cred"
   }
+  timeouts {
+    create = "45m"
+    delete = "10m"
+  }
 }
 
 output "ansible-forms_job_resource" {
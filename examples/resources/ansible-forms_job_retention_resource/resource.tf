@@ -0,0 +1,11 @@
+resource "ansible-forms_job_retention_resource" "cleanup" {
+  cx_profile_name = "cluster1"
+  retention_days  = 30
+  plan_only       = true
+  # change it to run the cleanup again
+  rerun_on = "2024-06"
+}
+
+output "ansible-forms_job_retention_resource" {
+  value = ansible-forms_job_retention_resource.cleanup.matched_count
+}
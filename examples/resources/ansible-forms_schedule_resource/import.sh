@@ -0,0 +1,2 @@
+# A schedule is imported with the connection profile name and the schedule id
+terraform import ansible-forms_schedule_resource.nightly cluster1,2
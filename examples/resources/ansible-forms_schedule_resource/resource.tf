@@ -0,0 +1,9 @@
+resource "ansible-forms_schedule_resource" "nightly" {
+  cx_profile_name = "cluster1"
+  form_name       = "Demo Form Ansible No input"
+  cron            = "0 2 * * 1-5"
+  enabled         = true
+  extravars = {
+    region = "eu"
+  }
+}
@@ -0,0 +1,2 @@
+# A user is imported with the connection profile name and the user id
+terraform import ansible-forms_user_resource.alice cluster1,5
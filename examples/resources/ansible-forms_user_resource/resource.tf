@@ -0,0 +1,7 @@
+resource "ansible-forms_user_resource" "alice" {
+  cx_profile_name = "cluster1"
+  username        = "alice"
+  email           = "alice@example.com"
+  role            = "operator"
+  password        = var.alice_password
+}
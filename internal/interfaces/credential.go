@@ -0,0 +1,166 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mitchellh/mapstructure"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// CredentialResourceModel describes the credential sent to Ansible Forms.
+type CredentialResourceModel struct {
+	Name        string `mapstructure:"name"`
+	User        string `mapstructure:"user"`
+	Password    string `mapstructure:"password,omitempty"`
+	Description string `mapstructure:"description"`
+	DBType      string `mapstructure:"db_type,omitempty"`
+}
+
+// CredentialGetDataSourceModel describes a credential read from Ansible Forms.  The password is never returned.
+type CredentialGetDataSourceModel struct {
+	ID          int64  `mapstructure:"id"`
+	Name        string `mapstructure:"name"`
+	User        string `mapstructure:"user"`
+	Description string `mapstructure:"description"`
+	DBType      string `mapstructure:"db_type"`
+}
+
+// GetCredentialByID gets credential info by id, nil when the credential does not exist.
+func GetCredentialByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) (*CredentialGetDataSourceModel, error) {
+	statusCode, response, err := r.GetNilOrOneRecord("credential/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorHandler.MakeAndReportError("error reading credential info", fmt.Sprintf("error on GET credential/%s: %s, statusCode %d", id, err, statusCode))
+	}
+	if response == nil {
+		return nil, nil
+	}
+
+	var apiResp dataResponse
+	if err = mapstructure.Decode(response, &apiResp); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET credential", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, restclient.Redact(response)))
+	}
+	if apiResp.Data == nil {
+		return nil, nil
+	}
+	var credential CredentialGetDataSourceModel
+	if err = mapstructure.WeakDecode(apiResp.Data, &credential); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET credential", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, restclient.Redact(response)))
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read credential info: %#v", credential))
+
+	return &credential, nil
+}
+
+// GetCredentials lists the credentials, an empty list when there is none.  Passwords are never returned.
+func GetCredentials(errorHandler *utils.ErrorHandler, r restclient.RestClient) ([]CredentialGetDataSourceModel, error) {
+	records, err := getRecords(errorHandler, r, "credential", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials := []CredentialGetDataSourceModel{}
+	if err = mapstructure.WeakDecode(records, &credentials); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET credential", fmt.Sprintf("error: %s, records %#v", err, restclient.Redact(records)))
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read %d credentials", len(credentials)))
+
+	return credentials, nil
+}
+
+// HasCredential reports whether a credential is named name.
+func HasCredential(credentials []CredentialGetDataSourceModel, name string) bool {
+	for _, credential := range credentials {
+		if credential.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateCredential creates a credential, and returns its id.
+func CreateCredential(errorHandler *utils.ErrorHandler, r restclient.RestClient, data CredentialResourceModel) (int64, error) {
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return 0, errorHandler.MakeAndReportError("error encoding credential body", fmt.Sprintf("error on encoding POST credential/ body: %s", err))
+	}
+
+	statusCode, response, err := r.CallCreateMethod("credential/", nil, body)
+	if err != nil {
+		return 0, errorHandler.MakeAndReportError("error creating credential", fmt.Sprintf("error on POST credential/: %s, statusCode %d", err, statusCode))
+	}
+	if len(response.Records) == 0 {
+		return 0, errorHandler.MakeAndReportError("error creating credential", fmt.Sprintf("no record returned by POST credential/, statusCode %d", statusCode))
+	}
+
+	var apiResp dataResponse
+	if err = mapstructure.Decode(response.Records[0], &apiResp); err != nil {
+		return 0, errorHandler.MakeAndReportError("failed to decode response from POST credential/", fmt.Sprintf("error: %s, statusCode %d", err, statusCode))
+	}
+	if apiResp.Status == "error" {
+		return 0, errorHandler.MakeAndReportError("error creating credential", fmt.Sprintf("error on POST credential/: %s, statusCode %d", apiResp.Message, statusCode))
+	}
+	id, ok := createdID(apiResp.Data)
+	if !ok {
+		return 0, errorHandler.MakeAndReportError("error creating credential", fmt.Sprintf("no id returned by POST credential/, statusCode %d, data %#v", statusCode, apiResp.Data))
+	}
+
+	return id, nil
+}
+
+// createdID reads the id of a created object, from data.output, data.output.id, or data.id.
+func createdID(data any) (int64, bool) {
+	if dataMap, ok := data.(map[string]any); ok {
+		if output, ok := dataMap["output"]; ok {
+			return createdID(output)
+		}
+		if id, ok := dataMap["id"]; ok {
+			return createdID(id)
+		}
+		if id, ok := dataMap["insertId"]; ok {
+			return createdID(id)
+		}
+		return 0, false
+	}
+	var id int64
+	if err := mapstructure.WeakDecode(data, &id); err != nil || id == 0 {
+		return 0, false
+	}
+
+	return id, true
+}
+
+// UpdateCredential replaces a credential.  The password is only changed when set.
+func UpdateCredential(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, data CredentialResourceModel) error {
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return errorHandler.MakeAndReportError("error encoding credential body", fmt.Sprintf("error on encoding PUT credential/%s body: %s", id, err))
+	}
+
+	statusCode, _, err := r.CallReplaceMethod("credential/"+id, nil, body)
+	if err != nil {
+		return errorHandler.MakeAndReportError("error updating credential", fmt.Sprintf("error on PUT credential/%s: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
+
+// DeleteCredentialByID deletes a credential by ID.  A credential that no longer exists is not an error.
+func DeleteCredentialByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) error {
+	statusCode, _, err := r.CallDeleteMethod("credential/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return errorHandler.MakeAndReportError("error deleting credential", fmt.Sprintf("error on DELETE credential/%s: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
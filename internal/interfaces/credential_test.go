@@ -0,0 +1,81 @@
+package interfaces
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestCreatedID(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   any
+		want   int64
+		wantOk bool
+	}{
+		{name: "output", data: map[string]any{"output": float64(12)}, want: 12, wantOk: true},
+		{name: "output_id", data: map[string]any{"output": map[string]any{"id": float64(13)}}, want: 13, wantOk: true},
+		{name: "insert_id", data: map[string]any{"output": map[string]any{"insertId": float64(14)}}, want: 14, wantOk: true},
+		{name: "missing", data: map[string]any{}, wantOk: false},
+		{name: "nil", data: nil, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := createdID(tt.data)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("createdID() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGetCredentialByID(t *testing.T) {
+	record := map[string]any{"status": "success", "message": "credential found", "data": map[string]any{"id": float64(3), "name": "ontap", "user": "admin", "description": "", "db_type": ""}}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		{ExpectedMethod: "GET", ExpectedURL: "credential/3", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{record}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	got, err := GetCredentialByID(errorHandler, *r, "3")
+	if err != nil {
+		t.Fatalf("GetCredentialByID() error = %v", err)
+	}
+	want := &CredentialGetDataSourceModel{ID: 3, Name: "ontap", User: "admin"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCredentialByID() = %#v, want %#v", got, want)
+	}
+}
+
+func TestGetCredentials(t *testing.T) {
+	page := map[string]any{"status": "success", "message": "credentials loaded", "data": []any{
+		map[string]any{"id": float64(1), "name": "ontap", "user": "admin"},
+		map[string]any{"id": "2", "name": "ldap", "user": "bind"},
+	}}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		{ExpectedMethod: "GET", ExpectedURL: "credential", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{page}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	got, err := GetCredentials(errorHandler, *r)
+	if err != nil {
+		t.Fatalf("GetCredentials() error = %v", err)
+	}
+	want := []CredentialGetDataSourceModel{{ID: 1, Name: "ontap", User: "admin"}, {ID: 2, Name: "ldap", User: "bind"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetCredentials() = %#v, want %#v", got, want)
+	}
+	if !HasCredential(got, "ldap") || HasCredential(got, "vault") {
+		t.Errorf("HasCredential() does not match the credential names of %#v", got)
+	}
+}
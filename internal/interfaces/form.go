@@ -0,0 +1,199 @@
+package interfaces
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mitchellh/mapstructure"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// FormFieldModel describes a field of a form.
+type FormFieldModel struct {
+	Name     string `mapstructure:"name"`
+	Type     string `mapstructure:"type"`
+	Label    string `mapstructure:"label"`
+	Required bool   `mapstructure:"required"`
+	Values   []any  `mapstructure:"values"`
+	Default  any    `mapstructure:"default"`
+	Multiple bool   `mapstructure:"multiple"`
+}
+
+// FormGetDataSourceModel describes a form.
+type FormGetDataSourceModel struct {
+	Name        string           `mapstructure:"name"`
+	Description string           `mapstructure:"description"`
+	Categories  []string         `mapstructure:"categories"`
+	Fields      []FormFieldModel `mapstructure:"fields"`
+}
+
+// RequiredInputs returns the names of the required fields.
+func (f FormGetDataSourceModel) RequiredInputs() []string {
+	var names []string
+	for _, field := range f.Fields {
+		if field.Required {
+			names = append(names, field.Name)
+		}
+	}
+
+	return names
+}
+
+// MissingInputs returns the names of the required fields without a default value, that are not set or empty in extravars.
+func (f FormGetDataSourceModel) MissingInputs(extravars map[string]any) []string {
+	var names []string
+	for _, field := range f.Fields {
+		if !field.Required || field.Default != nil {
+			continue
+		}
+		if value, ok := extravars[field.Name]; !ok || value == nil || value == "" {
+			names = append(names, field.Name)
+		}
+	}
+
+	return names
+}
+
+// InvalidInputs returns an error for each extravars value that is not one of the values allowed by its field.
+// Fields without a static list of values, and fields accepting multiple values, are not checked.
+func (f FormGetDataSourceModel) InvalidInputs(extravars map[string]any) JobFieldErrors {
+	var fieldErrors JobFieldErrors
+	for _, field := range f.Fields {
+		value, ok := extravars[field.Name]
+		if !ok || value == nil || value == "" || len(field.Values) == 0 || field.Multiple {
+			continue
+		}
+		allowed := field.allowedValues()
+		if !slices.Contains(allowed, fmt.Sprint(value)) {
+			fieldErrors = append(fieldErrors, JobFieldError{
+				Field:   field.Name,
+				Message: fmt.Sprintf("value %q is not allowed by form field %s, expected one of: %s", fmt.Sprint(value), field.Name, strings.Join(allowed, ", ")),
+			})
+		}
+	}
+
+	return fieldErrors
+}
+
+// allowedValues returns the values of an enum field.  A value given as an object is identified by its "value" or "name" key.
+func (f FormFieldModel) allowedValues() []string {
+	allowed := make([]string, 0, len(f.Values))
+	for _, value := range f.Values {
+		if object, ok := value.(map[string]any); ok {
+			if v, ok := object["value"]; ok {
+				value = v
+			} else {
+				value = object["name"]
+			}
+		}
+		allowed = append(allowed, fmt.Sprint(value))
+	}
+
+	return allowed
+}
+
+// GetForms lists forms, query may be nil.
+func GetForms(errorHandler *utils.ErrorHandler, r restclient.RestClient, query *restclient.RestQuery) ([]FormGetDataSourceModel, error) {
+	records, err := getRecords(errorHandler, r, "form", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var forms []FormGetDataSourceModel
+	if err = mapstructure.Decode(records, &forms); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET form", fmt.Sprintf("error: %s, records %#v", err, records))
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read %d forms", len(forms)))
+
+	return forms, nil
+}
+
+// FormsFilter selects forms in ListForms, empty fields do not filter.
+type FormsFilter struct {
+	Category string
+}
+
+// ListForms lists the forms matching filter, an empty list when there is none.
+func ListForms(errorHandler *utils.ErrorHandler, r restclient.RestClient, filter FormsFilter) ([]FormGetDataSourceModel, error) {
+	forms, err := GetForms(errorHandler, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := []FormGetDataSourceModel{}
+	for _, form := range forms {
+		if filter.Category != "" && !slices.Contains(form.Categories, filter.Category) {
+			continue
+		}
+		matching = append(matching, form)
+	}
+
+	return matching, nil
+}
+
+// FormSortKeys are the keys accepted by SortForms, FormSortServer keeps the order sent by the server.
+var FormSortKeys = []string{"name", FormSortServer}
+
+// FormSortServer keeps forms in the order sent by the server.
+const FormSortServer = "server"
+
+// SortForms sorts forms in place by key, one of FormSortKeys, in ascending order.
+func SortForms(forms []FormGetDataSourceModel, key string) {
+	if key == FormSortServer {
+		return
+	}
+	sort.SliceStable(forms, func(i, j int) bool {
+		return forms[i].Name < forms[j].Name
+	})
+}
+
+// CategoryGetDataSourceModel describes a category of forms, with the number of forms in the category.
+type CategoryGetDataSourceModel struct {
+	Name      string
+	FormCount int64
+}
+
+// ListCategories lists the categories of the forms, in the order they first appear, an empty list when there is none.
+// Ansible Forms has no category endpoint, categories are read from the forms.
+func ListCategories(errorHandler *utils.ErrorHandler, r restclient.RestClient) ([]CategoryGetDataSourceModel, error) {
+	forms, err := GetForms(errorHandler, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []CategoryGetDataSourceModel{}
+	indexes := make(map[string]int)
+	for _, form := range forms {
+		for _, name := range form.Categories {
+			index, ok := indexes[name]
+			if !ok {
+				index = len(categories)
+				indexes[name] = index
+				categories = append(categories, CategoryGetDataSourceModel{Name: name})
+			}
+			categories[index].FormCount++
+		}
+	}
+
+	return categories, nil
+}
+
+// GetFormByName gets form info by name.  An error is reported if the form does not exist.
+func GetFormByName(errorHandler *utils.ErrorHandler, r restclient.RestClient, name string) (*FormGetDataSourceModel, error) {
+	forms, err := GetForms(errorHandler, r, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, form := range forms {
+		if form.Name == name {
+			return &form, nil
+		}
+	}
+
+	return nil, errorHandler.MakeAndReportError("form not found", fmt.Sprintf("form %s does not exist", name))
+}
@@ -0,0 +1,227 @@
+package interfaces
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestGetFormByName(t *testing.T) {
+	forms := []any{
+		map[string]any{"name": "other", "fields": []any{}},
+		map[string]any{
+			"name":        "demo",
+			"description": "demo form",
+			"categories":  []any{"Default"},
+			"fields": []any{
+				map[string]any{"name": "vm_name", "type": "text", "label": "VM name", "required": true},
+				map[string]any{"name": "size", "type": "enum", "values": []any{"small", "large"}},
+			},
+		},
+	}
+	envelope := map[string]any{"status": "success", "message": "forms loaded", "data": forms}
+	response := restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "form", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{envelope}}}
+
+	tests := []struct {
+		name     string
+		formName string
+		want     *FormGetDataSourceModel
+		wantErr  bool
+	}{
+		{name: "found", formName: "demo", want: &FormGetDataSourceModel{
+			Name:        "demo",
+			Description: "demo form",
+			Categories:  []string{"Default"},
+			Fields: []FormFieldModel{
+				{Name: "vm_name", Type: "text", Label: "VM name", Required: true},
+				{Name: "size", Type: "enum", Values: []any{"small", "large"}},
+			},
+		}},
+		{name: "not found", formName: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			r, err := restclient.NewMockedRestClient([]restclient.MockResponse{response})
+			if err != nil {
+				panic(err)
+			}
+			got, err := GetFormByName(errorHandler, *r, tt.formName)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFormByName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFormByName() got = %#v, want %#v", got, tt.want)
+			}
+			if got != nil && !reflect.DeepEqual(got.RequiredInputs(), []string{"vm_name"}) {
+				t.Errorf("RequiredInputs() got = %v", got.RequiredInputs())
+			}
+		})
+	}
+}
+
+func TestFormGetDataSourceModel_MissingInputs(t *testing.T) {
+	form := FormGetDataSourceModel{
+		Name: "demo",
+		Fields: []FormFieldModel{
+			{Name: "vm_name", Required: true},
+			{Name: "datacenter", Required: true, Default: "dc1"},
+			{Name: "owner", Required: true},
+			{Name: "size"},
+		},
+	}
+	tests := []struct {
+		name      string
+		extravars map[string]any
+		want      []string
+	}{
+		{name: "none", extravars: nil, want: []string{"vm_name", "owner"}},
+		{name: "empty", extravars: map[string]any{"vm_name": "", "owner": "alice"}, want: []string{"vm_name"}},
+		{name: "all", extravars: map[string]any{"vm_name": "vm1", "owner": "alice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := form.MissingInputs(tt.extravars); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingInputs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormGetDataSourceModel_InvalidInputs(t *testing.T) {
+	form := FormGetDataSourceModel{
+		Name: "demo",
+		Fields: []FormFieldModel{
+			{Name: "vm_name"},
+			{Name: "size", Type: "enum", Values: []any{"small", "large"}},
+			{Name: "tier", Type: "enum", Values: []any{map[string]any{"name": "Gold", "value": "gold"}}},
+			{Name: "tags", Type: "enum", Values: []any{"a", "b"}, Multiple: true},
+		},
+	}
+	tests := []struct {
+		name      string
+		extravars map[string]any
+		want      []string
+	}{
+		{name: "none", extravars: nil},
+		{name: "allowed", extravars: map[string]any{"vm_name": "vm1", "size": "small", "tier": "gold", "tags": "a,c"}},
+		{name: "not_allowed", extravars: map[string]any{"size": "medium", "tier": "Gold"}, want: []string{"size", "tier"}},
+		{name: "empty", extravars: map[string]any{"size": ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, fieldError := range form.InvalidInputs(tt.extravars) {
+				got = append(got, fieldError.Field)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InvalidInputs() fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListForms(t *testing.T) {
+	page := func(forms ...any) map[string]any {
+		return map[string]any{"status": "success", "message": "forms loaded", "data": forms}
+	}
+	listResponse := func(pages ...map[string]any) restclient.MockResponse {
+		return restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "form", StatusCode: 200, Response: restclient.RestResponse{NumRecords: len(pages), Records: pages}}
+	}
+	storage := map[string]any{"name": "volume", "description": "create a volume", "categories": []any{"Storage", "Default"}}
+	network := map[string]any{"name": "vlan", "categories": []any{"Network"}}
+	uncategorized := map[string]any{"name": "demo"}
+
+	tests := []struct {
+		name      string
+		response  restclient.MockResponse
+		filter    FormsFilter
+		wantNames []string
+	}{
+		{name: "all", response: listResponse(page(storage, network, uncategorized)), wantNames: []string{"volume", "vlan", "demo"}},
+		{name: "pages", response: listResponse(page(storage), page(network, uncategorized)), wantNames: []string{"volume", "vlan", "demo"}},
+		{name: "category", response: listResponse(page(storage, network, uncategorized)), filter: FormsFilter{Category: "Storage"}, wantNames: []string{"volume"}},
+		{name: "unknown_category", response: listResponse(page(storage, network)), filter: FormsFilter{Category: "Compute"}, wantNames: []string{}},
+		{name: "empty", response: listResponse(page()), wantNames: []string{}},
+		{name: "no_records", response: listResponse(), wantNames: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			r, err := restclient.NewMockedRestClient([]restclient.MockResponse{tt.response})
+			if err != nil {
+				panic(err)
+			}
+			got, err := ListForms(errorHandler, *r, tt.filter)
+			if err != nil {
+				t.Fatalf("ListForms() error = %v", err)
+			}
+			names := []string{}
+			for _, form := range got {
+				names = append(names, form.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("ListForms() names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestSortForms(t *testing.T) {
+	forms := []FormGetDataSourceModel{{Name: "demo"}, {Name: "Backup"}, {Name: "cleanup"}}
+	SortForms(forms, "name")
+	if got := []string{forms[0].Name, forms[1].Name, forms[2].Name}; !reflect.DeepEqual(got, []string{"Backup", "cleanup", "demo"}) {
+		t.Errorf("SortForms(name) = %v, want Backup, cleanup, demo", got)
+	}
+	forms = []FormGetDataSourceModel{{Name: "demo"}, {Name: "Backup"}}
+	SortForms(forms, FormSortServer)
+	if forms[0].Name != "demo" {
+		t.Errorf("SortForms(server) = %v, want the server order", forms)
+	}
+}
+
+func TestListCategories(t *testing.T) {
+	listResponse := func(forms ...any) restclient.MockResponse {
+		page := map[string]any{"status": "success", "message": "forms loaded", "data": forms}
+		return restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "form", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{page}}}
+	}
+	storage := map[string]any{"name": "volume", "categories": []any{"Storage", "Default"}}
+	share := map[string]any{"name": "share", "categories": []any{"Storage"}}
+	uncategorized := map[string]any{"name": "demo"}
+
+	tests := []struct {
+		name     string
+		response restclient.MockResponse
+		want     []CategoryGetDataSourceModel
+	}{
+		{name: "counts", response: listResponse(storage, uncategorized, share),
+			want: []CategoryGetDataSourceModel{{Name: "Storage", FormCount: 2}, {Name: "Default", FormCount: 1}}},
+		{name: "uncategorized", response: listResponse(uncategorized), want: []CategoryGetDataSourceModel{}},
+		{name: "no_forms", response: listResponse(), want: []CategoryGetDataSourceModel{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			r, err := restclient.NewMockedRestClient([]restclient.MockResponse{tt.response})
+			if err != nil {
+				panic(err)
+			}
+			got, err := ListCategories(errorHandler, *r)
+			if err != nil {
+				t.Fatalf("ListCategories() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ListCategories() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
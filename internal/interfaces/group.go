@@ -0,0 +1,190 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mitchellh/mapstructure"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// GroupResourceModel describes the group sent to Ansible Forms.
+type GroupResourceModel struct {
+	Name string `mapstructure:"name"`
+	Role string `mapstructure:"role,omitempty"`
+}
+
+// GroupGetDataSourceModel describes a group read from Ansible Forms.
+type GroupGetDataSourceModel struct {
+	ID   int64  `mapstructure:"id"`
+	Name string `mapstructure:"name"`
+	Role string `mapstructure:"role"`
+}
+
+// groupMember describes a member of a group, as listed by GET group/<id>/members.
+type groupMember struct {
+	Username string `mapstructure:"username"`
+}
+
+// GetGroupByID gets group info by id, nil when the group does not exist.
+func GetGroupByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) (*GroupGetDataSourceModel, error) {
+	statusCode, response, err := r.GetNilOrOneRecord("group/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorHandler.MakeAndReportError("error reading group info", fmt.Sprintf("error on GET group/%s: %s, statusCode %d", id, err, statusCode))
+	}
+	if response == nil {
+		return nil, nil
+	}
+
+	var apiResp dataResponse
+	if err = mapstructure.Decode(response, &apiResp); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET group", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, response))
+	}
+	if apiResp.Data == nil {
+		return nil, nil
+	}
+	var group GroupGetDataSourceModel
+	if err = mapstructure.WeakDecode(apiResp.Data, &group); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET group", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, response))
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read group info: %#v", group))
+
+	return &group, nil
+}
+
+// CreateGroup creates a group, and returns its id.
+func CreateGroup(errorHandler *utils.ErrorHandler, r restclient.RestClient, data GroupResourceModel) (int64, error) {
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return 0, errorHandler.MakeAndReportError("error encoding group body", fmt.Sprintf("error on encoding POST group/ body: %s", err))
+	}
+
+	statusCode, response, err := r.CallCreateMethod("group/", nil, body)
+	if err != nil {
+		return 0, errorHandler.MakeAndReportError("error creating group", fmt.Sprintf("error on POST group/: %s, statusCode %d", err, statusCode))
+	}
+	if len(response.Records) == 0 {
+		return 0, errorHandler.MakeAndReportError("error creating group", fmt.Sprintf("no record returned by POST group/, statusCode %d", statusCode))
+	}
+
+	var apiResp dataResponse
+	if err = mapstructure.Decode(response.Records[0], &apiResp); err != nil {
+		return 0, errorHandler.MakeAndReportError("failed to decode response from POST group/", fmt.Sprintf("error: %s, statusCode %d", err, statusCode))
+	}
+	if apiResp.Status == "error" {
+		return 0, errorHandler.MakeAndReportError("error creating group", fmt.Sprintf("error on POST group/: %s, statusCode %d", apiResp.Message, statusCode))
+	}
+	id, ok := createdID(apiResp.Data)
+	if !ok {
+		return 0, errorHandler.MakeAndReportError("error creating group", fmt.Sprintf("no id returned by POST group/, statusCode %d, data %#v", statusCode, apiResp.Data))
+	}
+
+	return id, nil
+}
+
+// UpdateGroup replaces the name and role of a group.  Members are managed with AddGroupMember and RemoveGroupMember.
+func UpdateGroup(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, data GroupResourceModel) error {
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return errorHandler.MakeAndReportError("error encoding group body", fmt.Sprintf("error on encoding PUT group/%s body: %s", id, err))
+	}
+
+	statusCode, _, err := r.CallReplaceMethod("group/"+id, nil, body)
+	if err != nil {
+		return errorHandler.MakeAndReportError("error updating group", fmt.Sprintf("error on PUT group/%s: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
+
+// DeleteGroupByID deletes a group by ID.  A group that no longer exists is not an error.
+func DeleteGroupByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) error {
+	statusCode, _, err := r.CallDeleteMethod("group/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return errorHandler.MakeAndReportError("error deleting group", fmt.Sprintf("error on DELETE group/%s: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
+
+// GetGroupMembers returns the usernames of the members of a group, sorted.
+func GetGroupMembers(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) ([]string, error) {
+	records, err := getRecords(errorHandler, r, "group/"+id+"/members", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []groupMember
+	if err = mapstructure.Decode(records, &members); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET group members", fmt.Sprintf("error: %s, records %#v", err, records))
+	}
+	usernames := make([]string, 0, len(members))
+	for _, member := range members {
+		usernames = append(usernames, member.Username)
+	}
+	sort.Strings(usernames)
+
+	return usernames, nil
+}
+
+// AddGroupMember adds a user to a group.  When the user does not exist, the error reported by the server is returned as is.
+func AddGroupMember(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, username string) error {
+	statusCode, response, err := r.CallCreateMethod("group/"+id+"/members", nil, map[string]any{"username": username})
+	if err != nil {
+		if response.RestError.HasError() {
+			return errorHandler.MakeAndReportError(fmt.Sprintf("error adding %s to group", username), response.RestError.Detail())
+		}
+		return errorHandler.MakeAndReportError(fmt.Sprintf("error adding %s to group", username), fmt.Sprintf("error on POST group/%s/members: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
+
+// RemoveGroupMember removes a user from a group.  A user who is no longer a member is not an error.
+func RemoveGroupMember(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, username string) error {
+	// a username may hold a slash or other reserved characters, e.g. with a domain prefix
+	statusCode, _, err := r.CallDeleteMethod("group/"+id+"/members/"+url.PathEscape(username), nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return errorHandler.MakeAndReportError(fmt.Sprintf("error removing %s from group", username), fmt.Sprintf("error on DELETE group/%s/members: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
+
+// GroupMemberChanges returns the members to add and to remove to go from current to desired, each sorted.
+func GroupMemberChanges(current []string, desired []string) (add []string, remove []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, username := range current {
+		currentSet[username] = true
+	}
+	desiredSet := make(map[string]bool, len(desired))
+	for _, username := range desired {
+		desiredSet[username] = true
+		if !currentSet[username] {
+			add = append(add, username)
+		}
+	}
+	for _, username := range current {
+		if !desiredSet[username] {
+			remove = append(remove, username)
+		}
+	}
+	sort.Strings(add)
+	sort.Strings(remove)
+
+	return add, remove
+}
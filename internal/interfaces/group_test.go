@@ -0,0 +1,99 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestGroupMemberChanges(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    []string
+		desired    []string
+		wantAdd    []string
+		wantRemove []string
+	}{
+		{name: "no_change", current: []string{"alice", "bob"}, desired: []string{"bob", "alice"}},
+		{name: "add_and_remove", current: []string{"alice", "bob"}, desired: []string{"carol", "alice"}, wantAdd: []string{"carol"}, wantRemove: []string{"bob"}},
+		{name: "from_empty", current: nil, desired: []string{"bob", "alice"}, wantAdd: []string{"alice", "bob"}},
+		{name: "to_empty", current: []string{"bob", "alice"}, desired: nil, wantRemove: []string{"alice", "bob"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAdd, gotRemove := GroupMemberChanges(tt.current, tt.desired)
+			if !reflect.DeepEqual(gotAdd, tt.wantAdd) || !reflect.DeepEqual(gotRemove, tt.wantRemove) {
+				t.Errorf("GroupMemberChanges() = %v, %v, want %v, %v", gotAdd, gotRemove, tt.wantAdd, tt.wantRemove)
+			}
+		})
+	}
+}
+
+func TestGetGroupMembers(t *testing.T) {
+	record := map[string]any{"status": "success", "message": "", "data": []any{map[string]any{"username": "bob"}, map[string]any{"username": "alice"}}}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		{ExpectedMethod: "GET", ExpectedURL: "group/2/members", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{record}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	got, err := GetGroupMembers(errorHandler, *r, "2")
+	if err != nil {
+		t.Fatalf("GetGroupMembers() error = %v", err)
+	}
+	if want := []string{"alice", "bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetGroupMembers() = %v, want %v", got, want)
+	}
+}
+
+func TestAddGroupMember_unknownUser(t *testing.T) {
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		{ExpectedMethod: "POST", ExpectedURL: "group/2/members", StatusCode: 404,
+			Response: restclient.RestResponse{RestError: restclient.RestError{Message: "user carol not found"}}, Err: errors.New("REST reported error, statusCode: 404")},
+	})
+	if err != nil {
+		panic(err)
+	}
+	if err = AddGroupMember(errorHandler, *r, "2", "carol"); err == nil {
+		t.Fatalf("AddGroupMember() expected an error")
+	}
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail(), "user carol not found") {
+		t.Errorf("AddGroupMember() diagnostics = %v, want the server error", diags)
+	}
+}
+
+func TestRemoveGroupMember_escapesUsername(t *testing.T) {
+	var gotPath string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "success", "message": "member removed"}`))
+	}))
+	defer server.Close()
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+	r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := RemoveGroupMember(errorHandler, *r, "2", "corp/alice?x#1"); err != nil {
+		t.Fatalf("RemoveGroupMember() error = %v", err)
+	}
+	if want := "/api/v1/group/2/members/corp%2Falice%3Fx%231"; gotPath != want {
+		t.Errorf("RemoveGroupMember() path = %s, want %s", gotPath, want)
+	}
+}
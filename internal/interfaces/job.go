@@ -1,7 +1,17 @@
 package interfaces
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/mitchellh/mapstructure"
@@ -19,7 +29,7 @@ type JobResourceModel struct {
 	UserType    string         `mapstructure:"user_type"`
 	JobType     string         `mapstructure:"job_type"`
 	Extravars   map[string]any `mapstructure:"extravars"`
-	Credentials map[string]any `mapstructure:"credentials"`
+	Credentials map[string]any `mapstructure:"credentials,omitempty"`
 	Form        string         `mapstructure:"formName"`
 	Status      string         `mapstructure:"status"`
 	Message     string         `mapstructure:"message"`
@@ -29,6 +39,15 @@ type JobResourceModel struct {
 	Output      string         `mapstructure:"output"`
 	Data        string         `mapstructure:"data"`
 	Approval    string         `mapstructure:"approval"`
+	// CheckMode runs the playbook with --check, only sent when set
+	CheckMode bool `mapstructure:"checkMode,omitempty"`
+	// Limit and Tags are passed to ansible-playbook as --limit and --tags, Tags is comma separated
+	Limit string `mapstructure:"limit,omitempty"`
+	Tags  string `mapstructure:"tags,omitempty"`
+	// IdempotencyKey is sent as the Idempotency-Key header of the launch request, not in the body
+	IdempotencyKey string `mapstructure:"-"`
+	// RawPayload, when set, is sent as the launch body as is, rather than the body built from the other fields
+	RawPayload map[string]any `mapstructure:"-"`
 }
 
 // JobGetDataSourceModel ...
@@ -50,6 +69,10 @@ type JobGetDataSourceModel struct {
 	Output      string `mapstructure:"output"`
 	Data        string `mapstructure:"data"`
 	Approval    string `mapstructure:"approval"`
+	Playbook    string `mapstructure:"playbook"`
+	Inventory   string `mapstructure:"inventory"`
+	// QueuePosition is the position of a queued job in the server queue, nil when the server does not report it
+	QueuePosition *int64 `mapstructure:"queue_position"`
 }
 
 // GetJobResponse describes GET job response.
@@ -71,6 +94,32 @@ type CreateJobResponse struct {
 	} `json:"data"`
 }
 
+// jobNumberHook converts numbers sent as strings, such as "42" for a job id, so that a job id decodes the same whatever its JSON type.
+func jobNumberHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Int64 {
+		return data, nil
+	}
+	value := strings.TrimSpace(reflect.ValueOf(data).String())
+	if value == "" {
+		return int64(0), nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// decodeJobResponse decodes a job response or job records into output, accepting numbers sent as strings.
+func decodeJobResponse(input any, output any) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: jobNumberHook,
+		Result:     output,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
 // GetJobByID gets job info by id.
 func GetJobByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) (*JobGetDataSourceModel, error) {
 	statusCode, response, err := r.GetNilOrOneRecord("job/"+id, nil, nil)
@@ -78,41 +127,918 @@ func GetJobByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id st
 		return nil, errorHandler.MakeAndReportError("error reading job info", fmt.Sprintf("error on GET job/: %s, statusCode %d", err, statusCode))
 	}
 
+	return decodeJob(errorHandler, statusCode, response)
+}
+
+// FindJobByID gets job info by id, nil when the job does not exist.
+func FindJobByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) (*JobGetDataSourceModel, error) {
+	statusCode, response, err := r.GetAllowNotFound("job/"+id, nil, nil)
+	if err != nil {
+		return nil, errorHandler.MakeAndReportError("error reading job info", fmt.Sprintf("error on GET job/: %s, statusCode %d", err, statusCode))
+	}
+	job, err := decodeJob(errorHandler, statusCode, response)
+	if err != nil || job == nil || job.ID == 0 {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// decodeJob decodes a GET job/ response, nil when there is no record.
+func decodeJob(errorHandler *utils.ErrorHandler, statusCode int, response map[string]any) (*JobGetDataSourceModel, error) {
+	if response == nil {
+		return nil, nil
+	}
+
 	var apiResp *GetJobResponse
-	if err = mapstructure.Decode(response, &apiResp); err != nil {
+	if err := decodeJobResponse(response, &apiResp); err != nil {
 		return nil, errorHandler.MakeAndReportError("failed to decode response from GET job", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, response))
 	}
 	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read job info: %#v", apiResp.Data))
 
-	apiResp.Data.Status = apiResp.Status
+	// the envelope status reports whether the request succeeded, prefer the job status when present.
+	if apiResp.Data.Status == "" {
+		apiResp.Data.Status = apiResp.Status
+	}
 
 	return &apiResp.Data, nil
 }
 
+// GetJobs lists jobs, query may be nil.
+func GetJobs(errorHandler *utils.ErrorHandler, r restclient.RestClient, query *restclient.RestQuery) ([]JobGetDataSourceModel, error) {
+	jobs, _, err := getJobs(errorHandler, r, query, nil)
+
+	return jobs, err
+}
+
+// getJobs gets jobs as GetJobs, and the total number of jobs reported by the server, -1 when it is not reported.
+// enough, when not nil, is called with the jobs of each page, and the next pages are not read once it returns true.
+func getJobs(errorHandler *utils.ErrorHandler, r restclient.RestClient, query *restclient.RestQuery, enough func(jobs []JobGetDataSourceModel) bool) ([]JobGetDataSourceModel, int64, error) {
+	var enoughRecords func(records []map[string]any) bool
+	if enough != nil {
+		enoughRecords = func(records []map[string]any) bool {
+			var jobs []JobGetDataSourceModel
+			// a page that cannot be decoded is reported once all pages are read
+			return decodeJobResponse(records, &jobs) == nil && enough(jobs)
+		}
+	}
+	records, total, err := getRecordsWithTotal(errorHandler, r, "job", query, enoughRecords)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	var jobs []JobGetDataSourceModel
+	if err = decodeJobResponse(records, &jobs); err != nil {
+		return nil, -1, errorHandler.MakeAndReportError("failed to decode response from GET jobs", fmt.Sprintf("error: %s, records %#v", err, records))
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read %d jobs, total %d", len(jobs), total))
+
+	return jobs, total, nil
+}
+
+// JobsFilter selects jobs in ListJobs, empty fields do not filter.
+type JobsFilter struct {
+	Status string
+	Form   string
+	// Limit is the maximum number of jobs returned, 0 means no limit
+	Limit int
+}
+
+// ListJobs lists the jobs matching filter, and returns the total number of jobs reported by the server, -1 when it is not reported.
+// The filter is sent as query parameters, and also applied to the result in case the server ignores them.
+// With a limit, the next pages are not read once limit jobs match.
+func ListJobs(errorHandler *utils.ErrorHandler, r restclient.RestClient, filter JobsFilter) ([]JobGetDataSourceModel, int64, error) {
+	query := r.NewQuery()
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.Form != "" {
+		query.Set("formName", filter.Form)
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var enough func(jobs []JobGetDataSourceModel) bool
+	if filter.Limit > 0 {
+		// stop reading pages once limit jobs match, in case the server ignores the limit
+		found := map[int64]bool{}
+		enough = func(jobs []JobGetDataSourceModel) bool {
+			for _, job := range jobs {
+				if filter.matches(job) {
+					found[job.ID] = true
+				}
+			}
+			return len(found) >= filter.Limit
+		}
+	}
+	jobs, total, err := getJobs(errorHandler, r, query, enough)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	matching := []JobGetDataSourceModel{}
+	for _, job := range jobs {
+		if !filter.matches(job) {
+			continue
+		}
+		if filter.Limit > 0 && len(matching) >= filter.Limit {
+			break
+		}
+		matching = append(matching, job)
+	}
+
+	return matching, total, nil
+}
+
+// matches reports whether job has the status and form of filter, when they are set.
+func (filter JobsFilter) matches(job JobGetDataSourceModel) bool {
+	return (filter.Status == "" || job.Status == filter.Status) && (filter.Form == "" || job.Form == filter.Form)
+}
+
+// ListJobsFinishedBefore lists the jobs that completed before cutoff, reading all the pages of jobs.
+// Jobs still running, or without a valid end time, are never returned.
+func ListJobsFinishedBefore(errorHandler *utils.ErrorHandler, r restclient.RestClient, cutoff time.Time) ([]JobGetDataSourceModel, error) {
+	jobs, err := GetJobs(errorHandler, r, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	finished := []JobGetDataSourceModel{}
+	for _, job := range jobs {
+		if finishedAt, ok := job.FinishedAt(); ok && finishedAt.Before(cutoff) {
+			finished = append(finished, job)
+		}
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("%d of %d jobs finished before %s", len(finished), len(jobs), cutoff.Format(time.RFC3339)))
+
+	return finished, nil
+}
+
+// JobSortKeys are the keys accepted by SortJobs, JobSortServer keeps the order sent by the server.
+var JobSortKeys = []string{"id", "start", "end", "status", "form_name", JobSortServer}
+
+// JobSortServer keeps jobs in the order sent by the server.
+const JobSortServer = "server"
+
+// SortJobs sorts jobs in place by key, one of JobSortKeys, in ascending order, by id when the keys are equal.
+// Jobs without a start or end time are sorted last by start or end.
+func SortJobs(jobs []JobGetDataSourceModel, key string) {
+	if key == JobSortServer {
+		return
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		a, b := jobs[i], jobs[j]
+		switch key {
+		case "start", "end":
+			timeA, okA := a.StartedAt()
+			timeB, okB := b.StartedAt()
+			if key == "end" {
+				timeA, okA = a.FinishedAt()
+				timeB, okB = b.FinishedAt()
+			}
+			if okA != okB {
+				return okA
+			}
+			if okA && !timeA.Equal(timeB) {
+				return timeA.Before(timeB)
+			}
+		case "status":
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+		case "form_name":
+			if a.Form != b.Form {
+				return a.Form < b.Form
+			}
+		}
+
+		return a.ID < b.ID
+	})
+}
+
+// EncodeExtravars returns the JSON encoding of extra vars as sent in the launch request of a job, with sorted keys.
+// Numbers kept as json.Number are sent as written.  CreateJob builds the extra vars of the launch request with it.
+func EncodeExtravars(extravars map[string]any) (string, error) {
+	if extravars == nil {
+		extravars = map[string]any{}
+	}
+	encoded, err := json.Marshal(extravars)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// HashJobVariables returns a stable hash of a form name and its extra vars.
+// json.Marshal sorts map keys, so the result does not depend on key order.
+func HashJobVariables(formName string, extravars map[string]any) (string, error) {
+	if extravars == nil {
+		extravars = map[string]any{}
+	}
+	payload, err := json.Marshal(map[string]any{"form": formName, "extravars": extravars})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// maxDuplicateCheckJobs caps the number of jobs FindRecentDuplicateJob reads one by one, when the list does not carry their extra vars.
+const maxDuplicateCheckJobs = 20
+
+// FindRecentDuplicateJob looks for a job with the same form and extra vars submitted within window, the most recent first.
+// Failed or aborted jobs are never returned.  A nil job and nil error are returned when there is no match.
+// A job is read on its own when the list does not carry its extra vars, for up to maxDuplicateCheckJobs jobs.
+func FindRecentDuplicateJob(errorHandler *utils.ErrorHandler, r restclient.RestClient, data JobResourceModel, window time.Duration) (*JobGetDataSourceModel, error) {
+	wantHash, err := HashJobVariables(data.Form, data.Extravars)
+	if err != nil {
+		return nil, errorHandler.MakeAndReportError("error hashing job variables", fmt.Sprintf("error: %s, extravars: %#v", err, data.Extravars))
+	}
+
+	jobs, _, err := ListJobs(errorHandler, r, JobsFilter{Form: data.Form})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return isJobMoreRecent(jobs[i], jobs[j])
+	})
+
+	since := time.Now().Add(-window)
+	fetched := 0
+	for _, job := range jobs {
+		start, err := parseJobTime(job.Start)
+		if err == nil && start.Before(since) {
+			// the jobs that follow are older
+			break
+		}
+		if err != nil || IsJobFailed(job.Status) {
+			continue
+		}
+		candidate := &job
+		if candidate.Extravars == "" {
+			if fetched >= maxDuplicateCheckJobs {
+				tflog.Info(errorHandler.Ctx, fmt.Sprintf("checked %d jobs of form %s for a duplicate, not reading older jobs", fetched, data.Form))
+				break
+			}
+			// the list may not carry extra vars, fetch the full record.
+			candidate, err = GetJobByID(errorHandler, r, fmt.Sprint(job.ID))
+			if err != nil {
+				return nil, err
+			}
+			fetched++
+			// the job may have been deleted since it was listed
+			if candidate == nil {
+				continue
+			}
+			candidate.ID = job.ID
+		}
+		var extravars map[string]any
+		if candidate.Extravars != "" {
+			decoder := json.NewDecoder(strings.NewReader(candidate.Extravars))
+			// keep numbers as sent, as extravars_json does
+			decoder.UseNumber()
+			if err := decoder.Decode(&extravars); err != nil {
+				tflog.Debug(errorHandler.Ctx, fmt.Sprintf("skipping job %d, unable to decode extravars: %s", job.ID, err))
+				continue
+			}
+		}
+		gotHash, err := HashJobVariables(data.Form, extravars)
+		if err != nil {
+			continue
+		}
+		if gotHash == wantHash {
+			tflog.Debug(errorHandler.Ctx, fmt.Sprintf("found duplicate job %d for form %s", job.ID, data.Form))
+			return candidate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// maxExtravarSearchJobs caps the number of recent jobs FindJobsByExtravar searches.
+const maxExtravarSearchJobs = 200
+
+// FindJobsByExtravar lists the jobs launched with the extra var name set to value, e.g. a correlation id, the most recent first.
+// formName, when set, restricts the search to the jobs of this form.  Values that are not strings are compared with their JSON encoding,
+// e.g. 42 or true.  An empty list and nil error are returned when there is no match.
+// Only the maxExtravarSearchJobs most recent jobs are searched, newest first.  A job is read on its own when the list does not carry
+// its extra vars, until the first match: older jobs are then only compared when the list carries their extra vars.
+func FindJobsByExtravar(errorHandler *utils.ErrorHandler, r restclient.RestClient, formName string, name string, value string) ([]JobGetDataSourceModel, error) {
+	jobs, _, err := ListJobs(errorHandler, r, JobsFilter{Form: formName, Limit: maxExtravarSearchJobs})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return isJobMoreRecent(jobs[i], jobs[j])
+	})
+
+	matching := []JobGetDataSourceModel{}
+	fetched := 0
+	for _, job := range jobs {
+		candidate := &job
+		if candidate.Extravars == "" {
+			if len(matching) > 0 {
+				continue
+			}
+			// the list may not carry extra vars, fetch the full record.
+			candidate, err = GetJobByID(errorHandler, r, fmt.Sprint(job.ID))
+			if err != nil {
+				return nil, err
+			}
+			fetched++
+			// the job may have been deleted since it was listed
+			if candidate == nil {
+				continue
+			}
+			candidate.ID = job.ID
+		}
+		if extravarMatches(errorHandler, *candidate, name, value) {
+			matching = append(matching, *candidate)
+		}
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("%d of %d jobs have extra var %s set to %s, %d jobs read", len(matching), len(jobs), name, value, fetched))
+
+	return matching, nil
+}
+
+// extravarMatches reports whether the extra var name of job is value, values that are not strings are compared with their JSON encoding.
+func extravarMatches(errorHandler *utils.ErrorHandler, job JobGetDataSourceModel, name string, value string) bool {
+	if job.Extravars == "" {
+		return false
+	}
+	decoder := json.NewDecoder(strings.NewReader(job.Extravars))
+	// keep numbers as sent, e.g. a large id is not rounded
+	decoder.UseNumber()
+	var extravars map[string]any
+	if err := decoder.Decode(&extravars); err != nil {
+		tflog.Debug(errorHandler.Ctx, fmt.Sprintf("skipping job %d, unable to decode extravars: %s", job.ID, err))
+		return false
+	}
+	got, ok := extravars[name]
+	if !ok {
+		return false
+	}
+	if text, ok := got.(string); ok {
+		return text == value
+	}
+	encoded, err := json.Marshal(got)
+
+	return err == nil && string(encoded) == value
+}
+
+// isJobMoreRecent reports whether job a started after job b, by id when a start time is missing or equal.
+func isJobMoreRecent(a JobGetDataSourceModel, b JobGetDataSourceModel) bool {
+	startA, errA := parseJobTime(a.Start)
+	startB, errB := parseJobTime(b.Start)
+	if errA == nil && errB == nil && !startA.Equal(startB) {
+		return startA.After(startB)
+	}
+
+	return a.ID > b.ID
+}
+
+// parseJobTime parses the start or end time of a job.
+func parseJobTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.ParseInLocation("2006-01-02 15:04:05", value, time.UTC)
+}
+
+// StartedAt returns the start time of a job, false when the server did not return a valid one.
+func (j JobGetDataSourceModel) StartedAt() (time.Time, bool) {
+	t, err := parseJobTime(j.Start)
+	return t, err == nil
+}
+
+// FinishedAt returns the end time of a job, false when the job is not terminal or the server did not return a valid one.
+func (j JobGetDataSourceModel) FinishedAt() (time.Time, bool) {
+	if !IsJobTerminal(j.Status) {
+		return time.Time{}, false
+	}
+	t, err := parseJobTime(j.End)
+	return t, err == nil
+}
+
+// JobWaitOptions controls how WaitForJob polls a job.
+type JobWaitOptions struct {
+	// Timeout is how long to wait for the job to complete.
+	Timeout time.Duration
+	// PollInterval is the delay between the first two polls, doubled after each poll up to MaxPollInterval.
+	// The interval is fixed when MaxPollInterval is not greater than PollInterval.
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+	// ExtendOnProgress restarts Timeout whenever the job counter advances, without exceeding MaxTotalTimeout.
+	ExtendOnProgress bool
+	MaxTotalTimeout  time.Duration
+	// StreamOutput logs the new lines of the job output at Info level after each poll.
+	StreamOutput bool
+	// ReturnOnApproval returns the job as soon as it waits for an approval, rather than waiting for the approval and the run.
+	ReturnOnApproval bool
+	// MaxTransientErrors is the number of consecutive polls that may fail with a connection error or a 5xx status code
+	// before giving up, the count is reset by a successful poll. 0 gives up on the first error.
+	MaxTransientErrors int
+	// LongPollTimeout, when set, polls the job/{id}/wait endpoint, which the server holds until the job completes
+	// or this timeout expires. Polling falls back to GET job/{id} at PollInterval when the server does not support it.
+	LongPollTimeout time.Duration
+	// MaxQueueWait, when set, is how long the job may stay queued before WaitForJob gives up with an error,
+	// e.g. when the server runs its maximum number of concurrent jobs.  Timeout then only counts from when the job leaves the queue.
+	MaxQueueWait time.Duration
+}
+
+// pollDelay returns the delay before poll number poll + 1 (starting at 0), using capped exponential backoff,
+// with a jitter of up to 10% so that jobs launched together do not poll in lockstep.
+func (o JobWaitOptions) pollDelay(poll int) time.Duration {
+	wait := o.PollInterval
+	for i := 0; i < poll && wait < o.MaxPollInterval; i++ {
+		wait *= 2
+	}
+	if wait > o.MaxPollInterval && o.MaxPollInterval > o.PollInterval {
+		wait = o.MaxPollInterval
+	}
+	if jitter := int64(wait / 10); jitter > 0 {
+		wait -= time.Duration(rand.Int63n(jitter + 1))
+	}
+
+	return wait
+}
+
+// jobOutputCursor tracks the part of a job output already logged, so that each line is logged once.
+type jobOutputCursor struct {
+	offset int
+}
+
+// next returns the complete lines added to output since the last call.  A partial last line is kept for later,
+// unless final is set.  When output is shorter than what was logged, e.g. it was trimmed by the server, it is logged from the start.
+func (c *jobOutputCursor) next(output string, final bool) []string {
+	if c.offset > len(output) {
+		c.offset = 0
+	}
+	added := output[c.offset:]
+	if !final {
+		end := strings.LastIndex(added, "\n")
+		if end < 0 {
+			return nil
+		}
+		added = added[:end+1]
+	}
+	c.offset += len(added)
+	added = strings.TrimSuffix(added, "\n")
+	if added == "" {
+		return nil
+	}
+
+	return strings.Split(added, "\n")
+}
+
+// IsJobTerminal reports whether a job reached a final status.
+// Any other status, such as running, queued, abort (abort requested), or approve (waiting for approval), is still in progress.
+func IsJobTerminal(status string) bool {
+	return IsJobSuccess(status) || IsJobFailed(status)
+}
+
+// IsJobSuccess reports whether a job completed, possibly with warnings.
+func IsJobSuccess(status string) bool {
+	switch status {
+	case "success", "warning":
+		return true
+	}
+
+	return false
+}
+
+// IsJobFailed reports whether a job ended without completing.
+func IsJobFailed(status string) bool {
+	switch status {
+	case "failed", "error", "aborted", "canceled", "cancelled", "rejected":
+		return true
+	}
+
+	return false
+}
+
+// IsJobAwaitingApproval reports whether a job waits for an approval before its playbook runs.
+func IsJobAwaitingApproval(status string) bool {
+	switch status {
+	case "approve", "pending_approval", "waiting":
+		return true
+	}
+
+	return false
+}
+
+// IsJobQueued reports whether a job waits for the server to start it, e.g. while the server runs its maximum number of concurrent jobs.
+func IsJobQueued(status string) bool {
+	switch status {
+	case "queued", "pending":
+		return true
+	}
+
+	return false
+}
+
+// isJobRunning reports whether a job is still in progress.
+func isJobRunning(status string) bool {
+	return !IsJobTerminal(status)
+}
+
+// IsRunning reports whether the job is still in progress.
+func (j JobGetDataSourceModel) IsRunning() bool {
+	return isJobRunning(j.Status)
+}
+
+// IsAwaitingApproval reports whether the job waits for an approval.
+func (j JobGetDataSourceModel) IsAwaitingApproval() bool {
+	return IsJobAwaitingApproval(j.Status)
+}
+
+// IsQueued reports whether the job waits for the server to start it.
+func (j JobGetDataSourceModel) IsQueued() bool {
+	return IsJobQueued(j.Status)
+}
+
+// queuePositionSuffix describes the queue position of the job in diagnostics, empty when it is not reported.
+func (j JobGetDataSourceModel) queuePositionSuffix() string {
+	if j.QueuePosition == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(", at position %d in the queue", *j.QueuePosition)
+}
+
+// IsFailed reports whether the job ended without completing.
+func (j JobGetDataSourceModel) IsFailed() bool {
+	return IsJobFailed(j.Status)
+}
+
+// maxFailureReasonLength limits the failure reason reported for a failed job.
+const maxFailureReasonLength = 512
+
+// FailureReason returns a short reason for a failed job: the last failed task found in the output, or the job message.
+// Ansible reports a failed task as a "fatal:" line following the "TASK [name]" header.
+func (j JobGetDataSourceModel) FailureReason() string {
+	var task, failedTask, failedLine, lastLine string
+	for _, line := range JobOutputLines(j.Output) {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "TASK ["):
+			task = strings.TrimRight(strings.TrimPrefix(line, "TASK "), " *")
+		case strings.HasPrefix(line, "fatal:") || strings.Contains(line, "FAILED!"):
+			failedTask, failedLine = task, line
+		}
+		lastLine = line
+	}
+	var reason string
+	switch {
+	case failedLine != "" && failedTask != "":
+		reason = fmt.Sprintf("task %s failed: %s", failedTask, failedLine)
+	case failedLine != "":
+		reason = failedLine
+	case j.Message != "":
+		reason = j.Message
+	default:
+		reason = lastLine
+	}
+	reason, _ = TruncateJobOutput(reason, maxFailureReasonLength)
+
+	return reason
+}
+
+// TruncateJobOutput limits output to maxLength bytes, 0 meaning no limit, and reports whether it was truncated.
+func TruncateJobOutput(output string, maxLength int) (string, bool) {
+	if maxLength <= 0 || len(output) <= maxLength {
+		return output, false
+	}
+	// do not split a multi-byte character.
+	return strings.ToValidUTF8(output[:maxLength], ""), true
+}
+
+// JobOutputLines splits a job output into lines, without the trailing empty line.
+func JobOutputLines(output string) []string {
+	if output == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimSuffix(line, "\r")
+	}
+
+	return lines
+}
+
+// isEndpointUnsupported reports whether a response means the server does not have this endpoint, e.g. job/{id}/wait.
+func isEndpointUnsupported(statusCode int) bool {
+	return statusCode == http.StatusNotFound || statusCode == http.StatusMethodNotAllowed || statusCode == http.StatusNotImplemented
+}
+
+// WaitForJob polls a job until it reaches a terminal status, or until the timeout expires or the context is canceled.
+// A failed job is returned without error, the caller decides how to report it.
+func WaitForJob(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, options JobWaitOptions) (*JobGetDataSourceModel, error) {
+	start := time.Now()
+	deadline := start.Add(options.Timeout)
+	hardDeadline := deadline
+	if options.ExtendOnProgress && options.MaxTotalTimeout > options.Timeout {
+		hardDeadline = start.Add(options.MaxTotalTimeout)
+	}
+	lastProgress := int64(-1)
+	lastProgressAt := start
+	var cursor jobOutputCursor
+	var job *JobGetDataSourceModel
+	transientErrors := 0
+	longPoll := options.LongPollTimeout > 0
+	// with MaxQueueWait, the job is bounded by queueDeadline rather than deadline until it is seen out of the queue
+	queued := options.MaxQueueWait > 0
+	queueDeadline := start.Add(options.MaxQueueWait)
+	pollDeadline := func() time.Time {
+		if queued {
+			return queueDeadline
+		}
+		return deadline
+	}
+
+	for poll := 0; ; poll++ {
+		baseURL := "job/" + id
+		var query *restclient.RestQuery
+		if longPoll {
+			// the server answers before the deadline, so that a job still running is reported as a timeout
+			wait := options.LongPollTimeout
+			if remaining := time.Until(pollDeadline()); remaining < wait {
+				wait = remaining
+			}
+			if wait < time.Second {
+				wait = time.Second
+			}
+			baseURL += "/wait"
+			query = r.NewQuery()
+			query.Set("timeout", strconv.Itoa(int(wait.Seconds())))
+		}
+		polledAt := time.Now()
+		statusCode, response, err := r.GetNilOrOneRecord(baseURL, query, nil)
+		if longPoll && err != nil && isEndpointUnsupported(statusCode) {
+			tflog.Warn(errorHandler.Ctx, fmt.Sprintf("GET %s is not supported, statusCode %d, falling back to interval polling", baseURL, statusCode))
+			longPoll = false
+			continue
+		}
+		if err != nil && restclient.IsTransientError(err) && transientErrors < options.MaxTransientErrors && time.Now().Before(pollDeadline()) {
+			// the server or the network may be briefly unavailable, this says nothing about the job, poll again after a delay
+			transientErrors++
+			tflog.Warn(errorHandler.Ctx, fmt.Sprintf("error polling job %s (%d of %d consecutive errors tolerated): %s, statusCode %d",
+				id, transientErrors, options.MaxTransientErrors, err, statusCode))
+			if err := waitForNextPoll(errorHandler, id, options.pollDelay(poll), pollDeadline()); err != nil {
+				return job, err
+			}
+			continue
+		}
+		if err != nil {
+			return job, errorHandler.MakeAndReportError("error reading job info",
+				fmt.Sprintf("error on GET job/: %s, statusCode %d, after %d consecutive errors", err, statusCode, transientErrors+1))
+		}
+		transientErrors = 0
+		polled, err := decodeJob(errorHandler, statusCode, response)
+		if err != nil {
+			return job, err
+		}
+		job = polled
+		running := isJobRunning(job.Status)
+		if options.StreamOutput {
+			for _, line := range cursor.next(job.Output, !running) {
+				tflog.Info(errorHandler.Ctx, line, map[string]interface{}{"job_id": id})
+			}
+		}
+		if !running {
+			return job, nil
+		}
+		if options.ReturnOnApproval && IsJobAwaitingApproval(job.Status) {
+			tflog.Info(errorHandler.Ctx, fmt.Sprintf("job %s is waiting for an approval, not waiting for it to complete", id))
+			return job, nil
+		}
+
+		now := time.Now()
+		if queued && job.IsQueued() {
+			if !now.Before(queueDeadline) {
+				return job, errorHandler.MakeAndReportError("job still queued",
+					fmt.Sprintf("job %s is still %s after %s%s, exceeding the maximum queue wait of %s, the server may be running its maximum number of concurrent jobs",
+						id, job.Status, now.Sub(start).Round(time.Second), job.queuePositionSuffix(), options.MaxQueueWait))
+			}
+			tflog.Debug(errorHandler.Ctx, fmt.Sprintf("job %s is %s%s, waiting until %s", id, job.Status, job.queuePositionSuffix(), queueDeadline.Format(time.RFC3339)))
+			if longPoll && time.Since(polledAt) >= options.PollInterval {
+				continue
+			}
+			if err := waitForNextPoll(errorHandler, id, options.pollDelay(poll), queueDeadline); err != nil {
+				return job, err
+			}
+			continue
+		}
+		if queued {
+			// the job left the queue, the time it spent queued does not count toward the timeout
+			queued = false
+			deadline = deadline.Add(now.Sub(start))
+			hardDeadline = hardDeadline.Add(now.Sub(start))
+			tflog.Debug(errorHandler.Ctx, fmt.Sprintf("job %s left the queue after %s, waiting until %s", id, now.Sub(start).Round(time.Second), deadline.Format(time.RFC3339)))
+		}
+		if job.Counter > lastProgress {
+			if options.ExtendOnProgress && lastProgress >= 0 {
+				deadline = now.Add(options.Timeout)
+				if deadline.After(hardDeadline) {
+					deadline = hardDeadline
+				}
+				tflog.Debug(errorHandler.Ctx, fmt.Sprintf("job %s progressed to %d, waiting until %s", id, job.Counter, deadline.Format(time.RFC3339)))
+			}
+			lastProgress = job.Counter
+			lastProgressAt = now
+		}
+		if !now.Before(deadline) {
+			return job, errorHandler.MakeAndReportError("timeout waiting for job",
+				fmt.Sprintf("job %s is still %s after %s, last progress (counter %d) observed at %s", id, job.Status, now.Sub(start).Round(time.Second), lastProgress, lastProgressAt.Format(time.RFC3339)))
+		}
+
+		if longPoll && time.Since(polledAt) >= options.PollInterval {
+			// the server held the request, poll again right away
+			continue
+		}
+		if err := waitForNextPoll(errorHandler, id, options.pollDelay(poll), deadline); err != nil {
+			return job, err
+		}
+	}
+}
+
+// waitForNextPoll sleeps for delay, or until deadline when it comes first, so that the last poll happens at the deadline
+// rather than up to a full interval after it. An error is reported when the context is done.
+func waitForNextPoll(errorHandler *utils.ErrorHandler, id string, delay time.Duration, deadline time.Time) error {
+	if remaining := time.Until(deadline); delay > remaining {
+		delay = remaining
+	}
+	select {
+	case <-errorHandler.Ctx.Done():
+		return errorHandler.MakeAndReportError("interrupted waiting for job", fmt.Sprintf("job %s: %s", id, errorHandler.Ctx.Err()))
+	case <-time.After(delay):
+	}
+
+	return nil
+}
+
+// JobFieldError describes a form field rejected when submitting a job.
+type JobFieldError struct {
+	Field   string
+	Message string
+}
+
+// JobFieldErrors is returned by CreateJob when the server rejects individual form fields.
+// Unlike other errors, they are not reported by CreateJob, so the caller can attach them to the matching attributes.
+type JobFieldErrors []JobFieldError
+
+func (e JobFieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fieldError := range e {
+		msgs[i] = fmt.Sprintf("%s: %s", fieldError.Field, fieldError.Message)
+	}
+
+	return "invalid form fields: " + strings.Join(msgs, ", ")
+}
+
+// ParseJobFieldErrors extracts field level errors from a failed job submission.
+// Fields are read from RestError.Target, and from an "errors" list at the top of the record or under "data".
+// Each element in the list identifies its field with "field", "name", or "target".
+func ParseJobFieldErrors(response restclient.RestResponse) JobFieldErrors {
+	var fieldErrors JobFieldErrors
+	if response.RestError.Target != "" {
+		fieldErrors = append(fieldErrors, JobFieldError{Field: response.RestError.Target, Message: response.RestError.Message})
+	}
+	for _, record := range response.Records {
+		errorList := record["errors"]
+		if data, ok := record["data"].(map[string]any); ok && errorList == nil {
+			errorList = data["errors"]
+		}
+		items, ok := errorList.([]any)
+		if !ok {
+			continue
+		}
+		for _, item := range items {
+			itemMap, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			var fieldError JobFieldError
+			for _, key := range []string{"field", "name", "target"} {
+				if field, ok := itemMap[key].(string); ok && field != "" {
+					fieldError.Field = field
+					break
+				}
+			}
+			if fieldError.Field == "" {
+				continue
+			}
+			fieldError.Message, _ = itemMap["message"].(string)
+			fieldErrors = append(fieldErrors, fieldError)
+		}
+	}
+
+	return fieldErrors
+}
+
 // CreateJob creates a job.
 func CreateJob(errorHandler *utils.ErrorHandler, r restclient.RestClient, data JobResourceModel) (*GetJobResponse, error) {
-	var body map[string]interface{}
-	if err := mapstructure.Decode(data, &body); err != nil {
+	body, err := jobLaunchBody(data)
+	if err != nil {
 		return nil, errorHandler.MakeAndReportError("error encoding job body", fmt.Sprintf("error on encoding POST job/ body: %s, body: %#v", err, data))
 	}
 
+	if data.IdempotencyKey != "" {
+		// a POST resent after a connection error is deduplicated by servers that support the header, others ignore it
+		r = r.WithHeaders(map[string]string{"Idempotency-Key": data.IdempotencyKey})
+	}
 	statusCode, response, err := r.CallCreateMethod("job/", nil, body) // Ansible Forms API does not allow querying.
 	if err != nil {
+		if fieldErrors := ParseJobFieldErrors(response); len(fieldErrors) > 0 {
+			return nil, fieldErrors
+		}
+		if response.RestError.HasError() {
+			return nil, errorHandler.MakeAndReportError("error creating job", fmt.Sprintf("error on POST job/, statusCode %d\n%s", statusCode, response.RestError.Detail()))
+		}
 		return nil, errorHandler.MakeAndReportError("error creating job", fmt.Sprintf("error on POST job/: %s, statusCode %d", err, statusCode))
 	}
 
 	var resp *CreateJobResponse
-	if err = mapstructure.Decode(response.Records[0], &resp); err != nil {
+	if err = decodeJobResponse(response.Records[0], &resp); err != nil {
 		return nil, errorHandler.MakeAndReportError("failed to decode response from POST job/", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, response))
 	}
 	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("Create svm source - udata: %#v", resp))
+	if resp.Status == "error" {
+		if fieldErrors := ParseJobFieldErrors(response); len(fieldErrors) > 0 {
+			return nil, fieldErrors
+		}
+		return nil, errorHandler.MakeAndReportError("error creating job", fmt.Sprintf("error on POST job/: %s %s, statusCode %d", resp.Message, resp.Data.Error, statusCode))
+	}
 
 	return &GetJobResponse{Data: JobGetDataSourceModel{ID: resp.Data.Output.ID, Status: resp.Status}}, nil
 }
 
-// DeleteJobByID deletes a job by ID.
+// jobLaunchBody returns the body of the launch request of a job, RawPayload when set.
+// The extra vars are encoded with EncodeExtravars, as encode_extra_vars does, and decoded back to a map so that they are redacted in logs.
+func jobLaunchBody(data JobResourceModel) (map[string]any, error) {
+	if data.RawPayload != nil {
+		return data.RawPayload, nil
+	}
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return nil, err
+	}
+	encoded, err := EncodeExtravars(data.Extravars)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(strings.NewReader(encoded))
+	// keep numbers as written
+	decoder.UseNumber()
+	var extravars map[string]any
+	if err := decoder.Decode(&extravars); err != nil {
+		return nil, err
+	}
+	body["extravars"] = extravars
+
+	return body, nil
+}
+
+// CancelJobByID aborts a job that is still running, and waits until the server reports a terminal status.
+// A job that no longer exists is not an error.
+func CancelJobByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, options JobWaitOptions) error {
+	job, err := FindJobByID(errorHandler, r, id)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		tflog.Debug(errorHandler.Ctx, fmt.Sprintf("job %s not found, nothing to cancel", id))
+		return nil
+	}
+	if !isJobRunning(job.Status) {
+		return nil
+	}
+
+	tflog.Info(errorHandler.Ctx, fmt.Sprintf("aborting job %s, status %s", id, job.Status))
+	statusCode, _, err := r.CallCreateMethod("job/"+id+"/abort", nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return errorHandler.MakeAndReportError("error aborting job", fmt.Sprintf("error on POST job/%s/abort: %s, statusCode %d", id, err, statusCode))
+	}
+
+	// error reporting done inside WaitForJob
+	_, err = WaitForJob(errorHandler, r, id, options)
+	return err
+}
+
+// DeleteJobByID deletes a job by ID.  A job that no longer exists is not an error.
 func DeleteJobByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) error {
 	statusCode, _, err := r.CallDeleteMethod("job/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
 	if err != nil {
 		return errorHandler.MakeAndReportError("error deleting job info", fmt.Sprintf("error on DELETE job/: %s, statusCode %d", err, statusCode))
 	}
@@ -0,0 +1,373 @@
+package interfaces
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// errNoJobOutput is returned when a JSON response does not have a data.output field.
+var errNoJobOutput = errors.New("no output in the response")
+
+// StreamJobOutput writes the output of a job to w as it is received, rather than reading it in memory, and returns its size in bytes.
+// The output is read from job/{id}/output, as text or as a JSON job record, or from the job record of job/{id}
+// when the server does not have this endpoint.
+func StreamJobOutput(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, w io.Writer) (int64, error) {
+	counter := &countingWriter{writer: w}
+	baseURL := "job/" + id + "/output"
+	statusCode, err := r.GetStream(baseURL, nil, func(contentType string, body io.Reader) error {
+		return copyJobOutput(counter, contentType, body)
+	})
+	if err != nil && isEndpointUnsupported(statusCode) && counter.count == 0 {
+		tflog.Debug(errorHandler.Ctx, fmt.Sprintf("GET %s is not supported, statusCode %d, reading the output from the job record", baseURL, statusCode))
+		baseURL = "job/" + id
+		statusCode, err = r.GetStream(baseURL, nil, func(_ string, body io.Reader) error {
+			return copyJobOutputJSON(counter, body)
+		})
+	}
+	if err != nil {
+		return counter.count, errorHandler.MakeAndReportError("error reading job output",
+			fmt.Sprintf("error on GET %s: %s, statusCode %d, after %d bytes of output", baseURL, err, statusCode, counter.count))
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read %d bytes of output of job %s", counter.count, id))
+
+	return counter.count, nil
+}
+
+// copyJobOutput copies a job output response to w: a JSON job record, or the output itself for any other content type.
+func copyJobOutput(w io.Writer, contentType string, body io.Reader) error {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if contentType != "" && mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		_, err := io.Copy(w, body)
+		return err
+	}
+
+	return copyJobOutputJSON(w, body)
+}
+
+// copyJobOutputJSON copies the data.output string of a JSON job response to w as it is read, unescaping it,
+// so that only a small buffer is held in memory however large the output is.  A null output is copied as empty.
+// The fields after the output are not read.
+func copyJobOutputJSON(w io.Writer, body io.Reader) error {
+	decoder := json.NewDecoder(body)
+	if err := expectJSONDelim(decoder, '{'); err != nil {
+		return err
+	}
+	var status, message string
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "data":
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if token != json.Delim('{') {
+				if err := skipJSONValue(decoder, token); err != nil {
+					return err
+				}
+				continue
+			}
+			found, err := copyOutputField(w, decoder, body)
+			if found || err != nil {
+				return err
+			}
+		case "status", "message":
+			var value any
+			if err := decoder.Decode(&value); err != nil {
+				return err
+			}
+			if key == "status" {
+				status = fmt.Sprint(value)
+			} else {
+				message = fmt.Sprint(value)
+			}
+		default:
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if err := skipJSONValue(decoder, token); err != nil {
+				return err
+			}
+		}
+	}
+	if status == "error" {
+		return fmt.Errorf("%w, status %s, message %s", errNoJobOutput, status, message)
+	}
+
+	return errNoJobOutput
+}
+
+// copyOutputField reads the fields of the object opened in decoder until output, and copies its value to w, see copyJobOutputJSON.
+// It returns false when the object has no output field.
+func copyOutputField(w io.Writer, decoder *json.Decoder, body io.Reader) (bool, error) {
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return false, err
+		}
+		if key != "output" {
+			token, err := decoder.Token()
+			if err != nil {
+				return false, err
+			}
+			if err := skipJSONValue(decoder, token); err != nil {
+				return false, err
+			}
+			continue
+		}
+		// the decoder would read the whole string in memory, read the value from the rest of the body instead
+		reader := bufio.NewReader(io.MultiReader(decoder.Buffered(), body))
+		writer := bufio.NewWriter(w)
+		if err := copyJSONStringValue(writer, reader); err != nil {
+			// keep the output received before the error, e.g. in a file
+			_ = writer.Flush()
+			return true, err
+		}
+		return true, writer.Flush()
+	}
+
+	return false, expectJSONDelim(decoder, '}')
+}
+
+// copyJSONStringValue reads a `: "value"` or `: null` object value from r, and writes the unescaped string to w.
+func copyJSONStringValue(w *bufio.Writer, r *bufio.Reader) error {
+	b, err := readNonSpace(r)
+	if err != nil {
+		return err
+	}
+	if b != ':' {
+		return fmt.Errorf("invalid JSON, expected ':' after output, got %q", b)
+	}
+	if b, err = readNonSpace(r); err != nil {
+		return err
+	}
+	switch b {
+	case '"':
+		return copyJSONString(w, r)
+	case 'n':
+		rest := make([]byte, 3)
+		if _, err := io.ReadFull(r, rest); err != nil || string(rest) != "ull" {
+			return fmt.Errorf("invalid JSON value for output")
+		}
+		return nil
+	default:
+		return fmt.Errorf("expected a string for output, got a value starting with %q", b)
+	}
+}
+
+// copyJSONString unescapes the JSON string read from r, after its opening quote, to w, up to its closing quote.
+// Invalid surrogates are replaced with U+FFFD, as encoding/json does.
+func copyJSONString(w *bufio.Writer, r *bufio.Reader) error {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		switch {
+		case b == '"':
+			return nil
+		case b < 0x20:
+			return fmt.Errorf("invalid control character %q in JSON string", b)
+		case b != '\\':
+			if err := w.WriteByte(b); err != nil {
+				return err
+			}
+			continue
+		}
+		escape, err := r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		switch escape {
+		case '"', '\\', '/':
+			err = w.WriteByte(escape)
+		case 'b':
+			err = w.WriteByte('\b')
+		case 'f':
+			err = w.WriteByte('\f')
+		case 'n':
+			err = w.WriteByte('\n')
+		case 'r':
+			err = w.WriteByte('\r')
+		case 't':
+			err = w.WriteByte('\t')
+		case 'u':
+			var value rune
+			if value, err = readJSONHex(r); err != nil {
+				return err
+			}
+			if utf16.IsSurrogate(value) {
+				value = readJSONLowSurrogate(r, value)
+			}
+			_, err = w.WriteRune(value)
+		default:
+			return fmt.Errorf("invalid escape \\%c in JSON string", escape)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// readJSONHex reads the 4 hexadecimal digits of a \u escape.
+func readJSONHex(r *bufio.Reader) (rune, error) {
+	digits := make([]byte, 4)
+	if _, err := io.ReadFull(r, digits); err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	value, err := strconv.ParseUint(string(digits), 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid escape \\u%s in JSON string", digits)
+	}
+
+	return rune(value), nil
+}
+
+// readJSONLowSurrogate combines the high surrogate with the \u escape that follows it, and returns U+FFFD when they do not form a pair.
+// The following escape is only consumed when it completes the pair.
+func readJSONLowSurrogate(r *bufio.Reader, high rune) rune {
+	next, err := r.Peek(6)
+	if err != nil || next[0] != '\\' || next[1] != 'u' {
+		return utf8.RuneError
+	}
+	low, err := strconv.ParseUint(string(next[2:]), 16, 16)
+	if err != nil {
+		return utf8.RuneError
+	}
+	value := utf16.DecodeRune(high, rune(low))
+	if value == utf8.RuneError {
+		return value
+	}
+	_, _ = r.Discard(6)
+
+	return value
+}
+
+// readNonSpace returns the next byte of r that is not JSON white space.
+func readNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		if !bytes.ContainsRune([]byte(" \t\r\n"), rune(b)) {
+			return b, nil
+		}
+	}
+}
+
+// expectJSONDelim reads the next token of decoder, and returns an error when it is not delim.
+func expectJSONDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("invalid JSON response, expected %s, got %v", delim, token)
+	}
+
+	return nil
+}
+
+// skipJSONValue skips the value starting with token, reading the tokens of an object or an array up to its end.
+func skipJSONValue(decoder *json.Decoder, token json.Token) error {
+	if token != json.Delim('{') && token != json.Delim('[') {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// unexpectedEOF reports io.EOF as io.ErrUnexpectedEOF, as a JSON string or value was not complete.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// countingWriter counts the bytes written to writer.
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.count += int64(n)
+
+	return n, err
+}
+
+// JobOutputBuffer keeps the first MaxLength bytes written to it, 0 meaning no limit, and counts the bytes written,
+// so that a streamed output can be kept in state with a bounded size, see TruncateJobOutput.
+type JobOutputBuffer struct {
+	MaxLength int
+	buffer    bytes.Buffer
+	size      int64
+}
+
+// Write keeps the start of p that fits in MaxLength, and always succeeds, so that the rest of the output can still be written
+// to another writer, e.g. a file.
+func (b *JobOutputBuffer) Write(p []byte) (int, error) {
+	b.size += int64(len(p))
+	kept := p
+	if b.MaxLength > 0 {
+		remaining := b.MaxLength - b.buffer.Len()
+		if remaining < 0 {
+			remaining = 0
+		}
+		if len(kept) > remaining {
+			kept = kept[:remaining]
+		}
+	}
+	b.buffer.Write(kept)
+
+	return len(p), nil
+}
+
+// Output returns the bytes kept, without a rune cut at MaxLength, and whether the output was truncated.
+func (b *JobOutputBuffer) Output() (string, bool) {
+	truncated := b.size > int64(b.buffer.Len())
+	if !truncated {
+		return b.buffer.String(), false
+	}
+
+	return strings.ToValidUTF8(b.buffer.String(), ""), true
+}
+
+// Size returns the number of bytes written, including those that were not kept.
+func (b *JobOutputBuffer) Size() int64 {
+	return b.size
+}
@@ -0,0 +1,156 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestCopyJobOutputJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{name: "output", body: `{"status": "success", "data": {"id": 1, "output": "line 1\nline 2\n", "status": "success"}}`, want: "line 1\nline 2\n"},
+		{name: "fields_before_output", body: `{"message": "job found", "data": {"extravars": {"a": [1, {"b": "}"}]}, "output" : "ok"}}`, want: "ok"},
+		{name: "escapes", body: `{"data": {"output": "tab\tquote\" slash\/ backslash\\ é 😀 <>"}}`, want: "tab\tquote\" slash/ backslash\\ é 😀 <>"},
+		{name: "lone_surrogate", body: `{"data": {"output": "\ud83dA"}}`, want: "�A"},
+		{name: "null_output", body: `{"data": {"output": null}}`, want: ""},
+		{name: "no_output", body: `{"status": "success", "data": {"id": 1}}`, wantErr: true},
+		{name: "error_status", body: `{"status": "error", "message": "job not found", "data": {}}`, wantErr: true},
+		{name: "not_a_string", body: `{"data": {"output": {"id": 1}}}`, wantErr: true},
+		{name: "truncated", body: `{"data": {"output": "line 1\nli`, want: "line 1\nli", wantErr: true},
+		{name: "not_an_object", body: `["output"]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			err := copyJobOutputJSON(&got, strings.NewReader(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("copyJobOutputJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.String() != tt.want {
+				t.Errorf("copyJobOutputJSON() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyJobOutputJSON_errorStatus(t *testing.T) {
+	err := copyJobOutputJSON(io.Discard, strings.NewReader(`{"status": "error", "message": "job not found"}`))
+	if !errors.Is(err, errNoJobOutput) || !strings.Contains(err.Error(), "job not found") {
+		t.Errorf("copyJobOutputJSON() error = %v, want %v with the message", err, errNoJobOutput)
+	}
+}
+
+func TestJobOutputBuffer(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxLength     int
+		writes        []string
+		want          string
+		wantTruncated bool
+	}{
+		{name: "no_limit", maxLength: 0, writes: []string{"ab", "cd"}, want: "abcd"},
+		{name: "fits", maxLength: 4, writes: []string{"ab", "cd"}, want: "abcd"},
+		{name: "truncated", maxLength: 3, writes: []string{"ab", "cd", "ef"}, want: "abc", wantTruncated: true},
+		{name: "rune_cut", maxLength: 3, writes: []string{"aé", "b"}, want: "aé", wantTruncated: true},
+		{name: "rune_cut_dropped", maxLength: 2, writes: []string{"aé"}, want: "a", wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := &JobOutputBuffer{MaxLength: tt.maxLength}
+			size := 0
+			for _, write := range tt.writes {
+				if n, err := buffer.Write([]byte(write)); err != nil || n != len(write) {
+					t.Fatalf("Write() = %d, %v, want %d, nil", n, err, len(write))
+				}
+				size += len(write)
+			}
+			got, truncated := buffer.Output()
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("Output() = %q, %v, want %q, %v", got, truncated, tt.want, tt.wantTruncated)
+			}
+			if buffer.Size() != int64(size) {
+				t.Errorf("Size() = %d, want %d", buffer.Size(), size)
+			}
+		})
+	}
+}
+
+// jobOutputServer serves the output of job 1, from job/1/output when supported, and from the job record of job/1.
+type jobOutputServer struct {
+	supported   bool
+	contentType string
+	requests    []string
+}
+
+func (s *jobOutputServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	switch {
+	case r.URL.Path == "/api/v1/job/1/output" && s.supported && s.contentType == "text/plain":
+		w.Header().Set("Content-Type", s.contentType)
+		_, _ = w.Write([]byte("line 1\nline 2\n"))
+	case r.URL.Path == "/api/v1/job/1/output" && s.supported, r.URL.Path == "/api/v1/job/1":
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "success", "message": "job found", "data": {"id": 1, "status": "success", "output": "line 1\nline 2\n"}}`))
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status": "error", "message": "not found"}`))
+	}
+}
+
+func TestStreamJobOutput(t *testing.T) {
+	tests := []struct {
+		name         string
+		stub         *jobOutputServer
+		id           string
+		wantRequests []string
+		wantErr      bool
+	}{
+		{name: "text", stub: &jobOutputServer{supported: true, contentType: "text/plain"}, id: "1", wantRequests: []string{"GET /api/v1/job/1/output"}},
+		{name: "json", stub: &jobOutputServer{supported: true}, id: "1", wantRequests: []string{"GET /api/v1/job/1/output"}},
+		{name: "fallback", stub: &jobOutputServer{}, id: "1", wantRequests: []string{"GET /api/v1/job/1/output", "GET /api/v1/job/1"}},
+		{name: "not_found", stub: &jobOutputServer{}, id: "2", wantRequests: []string{"GET /api/v1/job/2/output", "GET /api/v1/job/2"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(tt.stub)
+			defer server.Close()
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+			r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			var got strings.Builder
+			size, err := StreamJobOutput(errorHandler, *r, tt.id, &got)
+			if (err != nil) != tt.wantErr || diags.HasError() != tt.wantErr {
+				t.Fatalf("StreamJobOutput() error = %v, diags %v, wantErr %v", err, diags, tt.wantErr)
+			}
+			if !reflect.DeepEqual(tt.stub.requests, tt.wantRequests) {
+				t.Errorf("requests = %v, want %v", tt.stub.requests, tt.wantRequests)
+			}
+			if tt.wantErr {
+				return
+			}
+			if want := "line 1\nline 2\n"; got.String() != want || size != int64(len(want)) {
+				t.Errorf("StreamJobOutput() = %q, %d, want %q, %d", got.String(), size, want, len(want))
+			}
+		})
+	}
+}
@@ -0,0 +1,947 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestHashJobVariables(t *testing.T) {
+	tests := []struct {
+		name      string
+		form1     string
+		vars1     map[string]any
+		form2     string
+		vars2     map[string]any
+		wantEqual bool
+	}{
+		{name: "same_vars", form1: "f", vars1: map[string]any{"a": "1", "b": "2"}, form2: "f", vars2: map[string]any{"a": "1", "b": "2"}, wantEqual: true},
+		{name: "key_order", form1: "f", vars1: map[string]any{"a": "1", "b": "2", "c": "3"}, form2: "f", vars2: map[string]any{"c": "3", "a": "1", "b": "2"}, wantEqual: true},
+		{name: "nested_key_order", form1: "f", vars1: map[string]any{"a": map[string]any{"x": 1, "y": 2}}, form2: "f", vars2: map[string]any{"a": map[string]any{"y": 2, "x": 1}}, wantEqual: true},
+		{name: "nil_and_empty", form1: "f", vars1: nil, form2: "f", vars2: map[string]any{}, wantEqual: true},
+		{name: "different_value", form1: "f", vars1: map[string]any{"a": "1"}, form2: "f", vars2: map[string]any{"a": "2"}, wantEqual: false},
+		{name: "different_form", form1: "f", vars1: map[string]any{"a": "1"}, form2: "g", vars2: map[string]any{"a": "1"}, wantEqual: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash1, err := HashJobVariables(tt.form1, tt.vars1)
+			if err != nil {
+				t.Fatalf("HashJobVariables() error = %v", err)
+			}
+			hash2, err := HashJobVariables(tt.form2, tt.vars2)
+			if err != nil {
+				t.Fatalf("HashJobVariables() error = %v", err)
+			}
+			if (hash1 == hash2) != tt.wantEqual {
+				t.Errorf("HashJobVariables() got %s and %s, wantEqual %v", hash1, hash2, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestParseJobTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "rfc3339", value: "2024-05-06T12:34:56Z", wantErr: false},
+		{name: "rfc3339_millis", value: "2024-05-06T12:34:56.123Z", wantErr: false},
+		{name: "mysql", value: "2024-05-06 12:34:56", wantErr: false},
+		{name: "empty", value: "", wantErr: true},
+		{name: "garbage", value: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseJobTime(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("parseJobTime() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func jobStatusResponse(status string, counter int) restclient.MockResponse {
+	record := map[string]any{
+		"status": "success",
+		"data":   map[string]any{"id": 1, "status": status, "counter": counter},
+	}
+	return restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "job/1", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{record}}}
+}
+
+func TestWaitForJob(t *testing.T) {
+	// 20 polls at 5ms intervals take longer than the 50ms base timeout.
+	progressing := []restclient.MockResponse{}
+	stuck := []restclient.MockResponse{}
+	for i := 0; i < 20; i++ {
+		progressing = append(progressing, jobStatusResponse("running", i))
+		stuck = append(stuck, jobStatusResponse("running", 3))
+	}
+	progressing = append(progressing, jobStatusResponse("success", 20))
+	stuck = append(stuck, jobStatusResponse("success", 3))
+	stuckApproval := []restclient.MockResponse{}
+	stuckQueue := []restclient.MockResponse{}
+	for i := 0; i < 20; i++ {
+		stuckApproval = append(stuckApproval, jobStatusResponse("approve", 0))
+		stuckQueue = append(stuckQueue, jobStatusResponse("queued", 0))
+	}
+	// 10 polls at 5ms intervals in the queue take longer than the 30ms timeout, which only counts from when the job leaves the queue.
+	leavingQueue := append(append([]restclient.MockResponse{}, stuckQueue[:10]...), jobStatusResponse("running", 1), jobStatusResponse("success", 2))
+	connectionErr := restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "job/1", StatusCode: -1,
+		Err: &url.Error{Op: "Get", URL: "https://host/api/v1/job/1", Err: errors.New("connection refused")}}
+	definitiveErr := restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "job/1", StatusCode: 401, Err: errors.New("unauthorized")}
+
+	tests := []struct {
+		name       string
+		responses  []restclient.MockResponse
+		options    JobWaitOptions
+		wantStatus string
+		wantErr    bool
+	}{
+		{name: "steady_progress_extends_timeout", responses: progressing, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, ExtendOnProgress: true, MaxTotalTimeout: 5 * time.Second}, wantStatus: "success", wantErr: false},
+		{name: "failed_is_terminal", responses: []restclient.MockResponse{jobStatusResponse("running", 0), jobStatusResponse("failed", 1)}, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond}, wantStatus: "failed", wantErr: false},
+		{name: "fixed_timeout", responses: progressing, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond}, wantStatus: "running", wantErr: true},
+		{name: "no_progress_times_out", responses: stuck, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, ExtendOnProgress: true, MaxTotalTimeout: 5 * time.Second}, wantStatus: "running", wantErr: true},
+		{name: "hard_cap", responses: progressing, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, ExtendOnProgress: true, MaxTotalTimeout: 60 * time.Millisecond}, wantStatus: "running", wantErr: true},
+		{name: "backoff_capped_by_timeout", responses: stuck, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, MaxPollInterval: time.Minute}, wantStatus: "running", wantErr: true},
+		{name: "waits_through_approval", responses: []restclient.MockResponse{jobStatusResponse("approve", 0), jobStatusResponse("running", 1), jobStatusResponse("success", 2)}, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond}, wantStatus: "success", wantErr: false},
+		{name: "returns_on_approval", responses: []restclient.MockResponse{jobStatusResponse("running", 0), jobStatusResponse("approve", 1)}, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, ReturnOnApproval: true}, wantStatus: "approve", wantErr: false},
+		{name: "approval_times_out", responses: stuckApproval, options: JobWaitOptions{Timeout: 10 * time.Millisecond, PollInterval: 5 * time.Millisecond}, wantStatus: "approve", wantErr: true},
+		{name: "queue_wait_exceeded", responses: stuckQueue, options: JobWaitOptions{Timeout: 5 * time.Second, PollInterval: 5 * time.Millisecond, MaxQueueWait: 20 * time.Millisecond}, wantStatus: "queued", wantErr: true},
+		{name: "queue_time_not_counted", responses: leavingQueue, options: JobWaitOptions{Timeout: 30 * time.Millisecond, PollInterval: 5 * time.Millisecond, MaxQueueWait: time.Second}, wantStatus: "success", wantErr: false},
+		{name: "queue_time_counted_without_max_queue_wait", responses: leavingQueue, options: JobWaitOptions{Timeout: 30 * time.Millisecond, PollInterval: 5 * time.Millisecond}, wantStatus: "queued", wantErr: true},
+		{name: "transient_errors_tolerated", responses: []restclient.MockResponse{jobStatusResponse("running", 0), connectionErr, connectionErr, jobStatusResponse("success", 1)}, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, MaxTransientErrors: 2}, wantStatus: "success", wantErr: false},
+		{name: "transient_errors_count_reset", responses: []restclient.MockResponse{connectionErr, jobStatusResponse("running", 0), connectionErr, jobStatusResponse("success", 1)}, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, MaxTransientErrors: 1}, wantStatus: "success", wantErr: false},
+		{name: "too_many_transient_errors", responses: []restclient.MockResponse{jobStatusResponse("running", 0), connectionErr, connectionErr}, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, MaxTransientErrors: 1}, wantStatus: "running", wantErr: true},
+		{name: "definitive_error_not_tolerated", responses: []restclient.MockResponse{jobStatusResponse("running", 0), definitiveErr}, options: JobWaitOptions{Timeout: 50 * time.Millisecond, PollInterval: 5 * time.Millisecond, MaxTransientErrors: 3}, wantStatus: "running", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			r, err := restclient.NewMockedRestClient(tt.responses)
+			if err != nil {
+				panic(err)
+			}
+			got, err := WaitForJob(errorHandler, *r, "1", tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WaitForJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got == nil || got.Status != tt.wantStatus {
+				t.Errorf("WaitForJob() got = %#v, want status %s", got, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestJobWaitOptions_pollDelay(t *testing.T) {
+	options := JobWaitOptions{PollInterval: 2 * time.Second, MaxPollInterval: 30 * time.Second}
+	for poll, want := range []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second} {
+		// the jitter shortens the delay by up to 10%
+		if got := options.pollDelay(poll); got > want || got < want-want/10 {
+			t.Errorf("pollDelay(%d) = %s, want between %s and %s", poll, got, want-want/10, want)
+		}
+	}
+	fixed := JobWaitOptions{PollInterval: 2 * time.Second}
+	if got := fixed.pollDelay(5); got > 2*time.Second || got < 1800*time.Millisecond {
+		t.Errorf("pollDelay(5) without MaxPollInterval = %s, want about 2s", got)
+	}
+}
+
+func TestParseJobFieldErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response restclient.RestResponse
+		want     JobFieldErrors
+	}{
+		{name: "no_error", response: restclient.RestResponse{}, want: nil},
+		{name: "rest_error_without_target", response: restclient.RestResponse{RestError: restclient.RestError{Code: "1", Message: "bad form"}}, want: nil},
+		{name: "rest_error_target", response: restclient.RestResponse{RestError: restclient.RestError{Code: "1", Message: "required", Target: "svm_name"}},
+			want: JobFieldErrors{{Field: "svm_name", Message: "required"}}},
+		{name: "errors_list", response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{{"errors": []any{
+			map[string]any{"field": "size", "message": "must be a number"},
+			map[string]any{"name": "env", "message": "not allowed"},
+		}}}},
+			want: JobFieldErrors{{Field: "size", Message: "must be a number"}, {Field: "env", Message: "not allowed"}}},
+		{name: "errors_list_in_data", response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{{"status": "error", "data": map[string]any{"errors": []any{
+			map[string]any{"target": "region", "message": "unknown region"},
+			map[string]any{"message": "no field"},
+		}}}}},
+			want: JobFieldErrors{{Field: "region", Message: "unknown region"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseJobFieldErrors(tt.response)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseJobFieldErrors() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateJob_extravars(t *testing.T) {
+	tests := []struct {
+		name          string
+		extravars     map[string]any
+		checkMode     bool
+		limit         string
+		tags          string
+		wantExtravars map[string]any
+	}{
+		{name: "set", extravars: map[string]any{"vm_name": "vm1"}, wantExtravars: map[string]any{"vm_name": "vm1"}},
+		{name: "check_mode", extravars: map[string]any{"vm_name": "vm1"}, checkMode: true, wantExtravars: map[string]any{"vm_name": "vm1"}},
+		{name: "limit_and_tags", extravars: map[string]any{"vm_name": "vm1"}, limit: "web*", tags: "deploy,config", wantExtravars: map[string]any{"vm_name": "vm1"}},
+		// sent as {}, as encode_extra_vars returns
+		{name: "empty", extravars: map[string]any{}, wantExtravars: map[string]any{}},
+		{name: "nil", extravars: nil, wantExtravars: map[string]any{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body map[string]any
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("failed to decode request body: %s", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status": "success", "message": "job launched", "data": {"output": {"id": 7}}}`))
+			}))
+			defer server.Close()
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+			r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			got, err := CreateJob(errorHandler, *r, JobResourceModel{Form: "demo", Extravars: tt.extravars, CheckMode: tt.checkMode, Limit: tt.limit, Tags: tt.tags})
+			if err != nil {
+				t.Fatalf("CreateJob() error = %v", err)
+			}
+			if got.Data.ID != 7 {
+				t.Errorf("CreateJob() got ID = %d, want 7", got.Data.ID)
+			}
+			if extravars, ok := body["extravars"]; !ok || !reflect.DeepEqual(extravars, tt.wantExtravars) {
+				t.Errorf("CreateJob() request body = %#v, want extravars %#v", body, tt.wantExtravars)
+			}
+			if checkMode, ok := body["checkMode"]; ok != tt.checkMode || ok && checkMode != true {
+				t.Errorf("CreateJob() request body = %#v, want checkMode %v", body, tt.checkMode)
+			}
+			for key, want := range map[string]string{"limit": tt.limit, "tags": tt.tags} {
+				if got, ok := body[key]; ok != (want != "") || ok && got != want {
+					t.Errorf("CreateJob() request body = %#v, want %s %q", body, key, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateJob_typedExtravars(t *testing.T) {
+	var body []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			t.Errorf("failed to read request body: %s", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "success", "message": "job launched", "data": {"output": {"id": 7}}}`))
+	}))
+	defer server.Close()
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+	r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	extravars := map[string]any{
+		"name":     "vm1",
+		"enabled":  true,
+		"count":    json.Number("3"),
+		"ratio":    json.Number("0.5"),
+		"big":      json.Number("9007199254740993"),
+		"disks":    []any{map[string]any{"size": json.Number("10"), "thin": false}, "raw"},
+		"network":  map[string]any{"vlan": json.Number("42"), "dns": []any{"10.0.0.1", "10.0.0.2"}, "gateway": nil},
+		"disabled": false,
+	}
+	if _, err = CreateJob(errorHandler, *r, JobResourceModel{Form: "demo", Extravars: extravars}); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	var got struct {
+		Extravars json.RawMessage `json:"extravars"`
+	}
+	if err = json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("failed to decode request body %s: %s", body, err)
+	}
+	want := `{"big":9007199254740993,"count":3,"disabled":false,"disks":[{"size":10,"thin":false},"raw"],"enabled":true,` +
+		`"name":"vm1","network":{"dns":["10.0.0.1","10.0.0.2"],"gateway":null,"vlan":42},"ratio":0.5}`
+	if string(got.Extravars) != want {
+		t.Errorf("CreateJob() extravars = %s, want %s", got.Extravars, want)
+	}
+}
+
+func TestCreateJob_rawPayload(t *testing.T) {
+	var body []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if body, err = io.ReadAll(r.Body); err != nil {
+			t.Errorf("failed to read request body: %s", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "success", "message": "job launched", "data": {"output": {"id": 7}}}`))
+	}))
+	defer server.Close()
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+	r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	rawPayload := map[string]any{"formName": "exotic", "extravars": map[string]any{"size": json.Number("10")}, "awxCredentials": []any{"a", "b"}}
+	job, err := CreateJob(errorHandler, *r, JobResourceModel{Form: "ignored", Limit: "ignored", RawPayload: rawPayload})
+	if err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	if job.Data.ID != 7 {
+		t.Errorf("CreateJob() id = %d, want 7", job.Data.ID)
+	}
+	want := `{"awxCredentials":["a","b"],"extravars":{"size":10},"formName":"exotic"}`
+	if string(body) != want {
+		t.Errorf("CreateJob() body = %s, want %s", body, want)
+	}
+}
+
+func TestTruncateJobOutput(t *testing.T) {
+	tests := []struct {
+		name          string
+		output        string
+		maxLength     int
+		want          string
+		wantTruncated bool
+	}{
+		{name: "no limit", output: "hello", maxLength: 0, want: "hello"},
+		{name: "short", output: "hello", maxLength: 5, want: "hello"},
+		{name: "truncated", output: "hello world", maxLength: 5, want: "hello", wantTruncated: true},
+		{name: "multi-byte", output: "héllo", maxLength: 2, want: "h", wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := TruncateJobOutput(tt.output, tt.maxLength)
+			if got != tt.want || truncated != tt.wantTruncated {
+				t.Errorf("TruncateJobOutput() = %q, %v, want %q, %v", got, truncated, tt.want, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestJobOutputLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{name: "empty", output: "", want: nil},
+		{name: "one line", output: "ok", want: []string{"ok"}},
+		{name: "trailing newline", output: "TASK [x]\r\nok\n", want: []string{"TASK [x]", "ok"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := JobOutputLines(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JobOutputLines() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobGetDataSourceModel_FailureReason(t *testing.T) {
+	output := "PLAY [demo] ****\n\nTASK [Gathering Facts] ****\nok: [localhost]\n\nTASK [create volume] ****\n" +
+		"fatal: [localhost]: FAILED! => {\"msg\": \"volume exists\"}\n\nPLAY RECAP ****\nlocalhost : ok=1 failed=1\n"
+	tests := []struct {
+		name string
+		job  JobGetDataSourceModel
+		want string
+	}{
+		{name: "failed_task", job: JobGetDataSourceModel{Output: output, Message: "failed"},
+			want: `task [create volume] failed: fatal: [localhost]: FAILED! => {"msg": "volume exists"}`},
+		{name: "message", job: JobGetDataSourceModel{Output: "PLAY RECAP ****\n", Message: "playbook not found"}, want: "playbook not found"},
+		{name: "last_line", job: JobGetDataSourceModel{Output: "starting\nERROR! the playbook could not be found\n\n"}, want: "ERROR! the playbook could not be found"},
+		{name: "empty", job: JobGetDataSourceModel{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.FailureReason(); got != tt.want {
+				t.Errorf("FailureReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsJobTerminal(t *testing.T) {
+	tests := []struct {
+		status       string
+		wantTerminal bool
+		wantFailed   bool
+	}{
+		{status: "running"},
+		{status: "queued"},
+		{status: "approve"},
+		{status: "abort"},
+		{status: "success", wantTerminal: true},
+		{status: "warning", wantTerminal: true},
+		{status: "failed", wantTerminal: true, wantFailed: true},
+		{status: "error", wantTerminal: true, wantFailed: true},
+		{status: "aborted", wantTerminal: true, wantFailed: true},
+		{status: "canceled", wantTerminal: true, wantFailed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := IsJobTerminal(tt.status); got != tt.wantTerminal {
+				t.Errorf("IsJobTerminal() = %v, want %v", got, tt.wantTerminal)
+			}
+			if got := IsJobFailed(tt.status); got != tt.wantFailed {
+				t.Errorf("IsJobFailed() = %v, want %v", got, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestWaitForJob_canceled(t *testing.T) {
+	var diags diag.Diagnostics
+	ctx, cancel := context.WithCancel(context.Background())
+	errorHandler := utils.NewErrorHandler(ctx, &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{jobStatusResponse("running", 0)})
+	if err != nil {
+		panic(err)
+	}
+	cancel()
+	got, err := WaitForJob(errorHandler, *r, "1", JobWaitOptions{Timeout: time.Minute, PollInterval: time.Minute})
+	if err == nil {
+		t.Fatalf("WaitForJob() expected an error when the context is canceled")
+	}
+	if got == nil || got.Status != "running" {
+		t.Errorf("WaitForJob() got = %#v, want status running", got)
+	}
+}
+
+// jobServer is a stub AnsibleForms server for job 1, the job is aborted once the abort request is received.
+type jobServer struct {
+	status   string
+	requests []string
+}
+
+func (s *jobServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case s.status == "":
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status": "error", "message": "job not found"}`))
+	case r.Method == "GET":
+		_, _ = w.Write([]byte(`{"status": "success", "message": "job found", "data": {"id": 1, "status": "` + s.status + `"}}`))
+	case r.Method == "POST" && r.URL.Path == "/api/v1/job/1/abort":
+		s.status = "aborted"
+		_, _ = w.Write([]byte(`{"status": "success", "message": "job aborted", "data": {}}`))
+	case r.Method == "DELETE":
+		_, _ = w.Write([]byte(`{"status": "success", "message": "job deleted", "data": {}}`))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestCancelJobByID(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       string
+		wantRequests []string
+	}{
+		{name: "running", status: "running", wantRequests: []string{"GET /api/v1/job/1", "POST /api/v1/job/1/abort", "GET /api/v1/job/1", "DELETE /api/v1/job/1"}},
+		{name: "terminal", status: "success", wantRequests: []string{"GET /api/v1/job/1", "DELETE /api/v1/job/1"}},
+		{name: "not_found", status: "", wantRequests: []string{"GET /api/v1/job/1", "DELETE /api/v1/job/1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &jobServer{status: tt.status}
+			server := httptest.NewTLSServer(stub)
+			defer server.Close()
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+			r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			if err = CancelJobByID(errorHandler, *r, "1", JobWaitOptions{Timeout: time.Second, PollInterval: time.Millisecond}); err != nil {
+				t.Fatalf("CancelJobByID() error = %v", err)
+			}
+			if err = DeleteJobByID(errorHandler, *r, "1"); err != nil {
+				t.Fatalf("DeleteJobByID() error = %v", err)
+			}
+			if !reflect.DeepEqual(stub.requests, tt.wantRequests) {
+				t.Errorf("requests = %v, want %v", stub.requests, tt.wantRequests)
+			}
+		})
+	}
+}
+
+// longPollServer is a stub AnsibleForms server for job 1, with or without the job/{id}/wait endpoint.
+type longPollServer struct {
+	supported bool
+	requests  []string
+}
+
+func (s *longPollServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == "/api/v1/job/1/wait" && !s.supported {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"status": "error", "message": "not found"}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"status": "success", "message": "job found", "data": {"id": 1, "status": "success"}}`))
+}
+
+func TestWaitForJob_longPoll(t *testing.T) {
+	tests := []struct {
+		name         string
+		supported    bool
+		wantRequests []string
+	}{
+		{name: "supported", supported: true, wantRequests: []string{"GET /api/v1/job/1/wait?timeout=30"}},
+		{name: "fallback", supported: false, wantRequests: []string{"GET /api/v1/job/1/wait?timeout=30", "GET /api/v1/job/1?"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &longPollServer{supported: tt.supported}
+			server := httptest.NewTLSServer(stub)
+			defer server.Close()
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+			r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			got, err := WaitForJob(errorHandler, *r, "1", JobWaitOptions{Timeout: time.Minute, PollInterval: time.Millisecond, LongPollTimeout: 30 * time.Second})
+			if err != nil {
+				t.Fatalf("WaitForJob() error = %v", err)
+			}
+			if got == nil || got.Status != "success" {
+				t.Errorf("WaitForJob() got = %#v, want status success", got)
+			}
+			if !reflect.DeepEqual(stub.requests, tt.wantRequests) {
+				t.Errorf("requests = %v, want %v", stub.requests, tt.wantRequests)
+			}
+			if diags.HasError() {
+				t.Errorf("WaitForJob() reported %v, want no error for the fallback", diags)
+			}
+		})
+	}
+}
+
+func TestFindJobByID(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		want   *JobGetDataSourceModel
+	}{
+		{name: "found", status: "success", want: &JobGetDataSourceModel{ID: 1, Status: "success"}},
+		{name: "not_found", status: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(&jobServer{status: tt.status})
+			defer server.Close()
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+			r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			got, err := FindJobByID(errorHandler, *r, "1")
+			if err != nil {
+				t.Fatalf("FindJobByID() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindJobByID() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeJobResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      any
+		want    int64
+		wantErr bool
+	}{
+		{name: "int", id: 42, want: 42},
+		{name: "float", id: float64(42), want: 42},
+		{name: "json_number", id: json.Number("42"), want: 42},
+		{name: "string", id: "42", want: 42},
+		{name: "padded_string", id: " 42 ", want: 42},
+		{name: "empty_string", id: "", want: 0},
+		{name: "not_a_number", id: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var job GetJobResponse
+			err := decodeJobResponse(map[string]any{"status": "success", "data": map[string]any{"id": tt.id, "counter": "3"}}, &job)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeJobResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (job.Data.ID != tt.want || job.Data.Counter != 3) {
+				t.Errorf("decodeJobResponse() id = %d, counter = %d, want %d, 3", job.Data.ID, job.Data.Counter, tt.want)
+			}
+
+			var jobs []JobGetDataSourceModel
+			err = decodeJobResponse([]map[string]any{{"id": tt.id, "status": "success"}}, &jobs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeJobResponse() records error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (len(jobs) != 1 || jobs[0].ID != tt.want) {
+				t.Errorf("decodeJobResponse() records = %#v, want id %d", jobs, tt.want)
+			}
+
+			var created CreateJobResponse
+			err = decodeJobResponse(map[string]any{"status": "success", "data": map[string]any{"output": map[string]any{"id": tt.id}}}, &created)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeJobResponse() created error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && created.Data.Output.ID != tt.want {
+				t.Errorf("decodeJobResponse() created id = %d, want %d", created.Data.Output.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeJobResponse_queuePosition(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]any
+		want string
+	}{
+		{name: "number", data: map[string]any{"id": 1, "status": "queued", "queue_position": float64(3)}, want: ", at position 3 in the queue"},
+		{name: "string", data: map[string]any{"id": 1, "status": "queued", "queue_position": "3"}, want: ", at position 3 in the queue"},
+		{name: "not_reported", data: map[string]any{"id": 1, "status": "queued"}, want: ""},
+		{name: "null", data: map[string]any{"id": 1, "status": "queued", "queue_position": nil}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var job GetJobResponse
+			if err := decodeJobResponse(map[string]any{"status": "success", "data": tt.data}, &job); err != nil {
+				t.Fatalf("decodeJobResponse() error = %v", err)
+			}
+			if got := job.Data.queuePositionSuffix(); got != tt.want || !job.Data.IsQueued() {
+				t.Errorf("queuePositionSuffix() = %q, queued %v, want %q, true", got, job.Data.IsQueued(), tt.want)
+			}
+		})
+	}
+}
+
+func TestListJobs(t *testing.T) {
+	jobs := []any{
+		map[string]any{"id": 3, "formName": "demo", "status": "success"},
+		map[string]any{"id": 2, "formName": "demo", "status": "failed"},
+		map[string]any{"id": 1, "formName": "other", "status": "success"},
+	}
+	listResponse := func(data []any) restclient.MockResponse {
+		envelope := map[string]any{"status": "success", "message": "jobs loaded", "data": data}
+		return restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "job", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{envelope}}}
+	}
+
+	withTotal := listResponse(jobs)
+	withTotal.Response.Records[0]["total"] = float64(42)
+
+	tests := []struct {
+		name      string
+		response  restclient.MockResponse
+		filter    JobsFilter
+		wantIDs   []int64
+		wantTotal int64
+	}{
+		{name: "all", response: listResponse(jobs), filter: JobsFilter{}, wantIDs: []int64{3, 2, 1}, wantTotal: -1},
+		{name: "total", response: withTotal, filter: JobsFilter{Status: "success"}, wantIDs: []int64{3, 1}, wantTotal: 42},
+		{name: "status", response: listResponse(jobs), filter: JobsFilter{Status: "success"}, wantIDs: []int64{3, 1}, wantTotal: -1},
+		{name: "form_and_limit", response: listResponse(jobs), filter: JobsFilter{Form: "demo", Limit: 1}, wantIDs: []int64{3}, wantTotal: -1},
+		{name: "empty", response: listResponse([]any{}), filter: JobsFilter{}, wantIDs: []int64{}, wantTotal: -1},
+		{name: "no_records", response: restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "job", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 0}}, filter: JobsFilter{}, wantIDs: []int64{}, wantTotal: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			r, err := restclient.NewMockedRestClient([]restclient.MockResponse{tt.response})
+			if err != nil {
+				panic(err)
+			}
+			got, total, err := ListJobs(errorHandler, *r, tt.filter)
+			if err != nil {
+				t.Fatalf("ListJobs() error = %v", err)
+			}
+			ids := []int64{}
+			for _, job := range got {
+				ids = append(ids, job.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ListJobs() ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("ListJobs() total = %d, want %d", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestListJobs_limitStopsPaging(t *testing.T) {
+	page := func(next string, data ...any) restclient.MockResponse {
+		envelope := map[string]any{"status": "success", "message": "jobs loaded", "data": data}
+		return restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "job", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{envelope}, NextHref: next}}
+	}
+	// the third page is not read, the first two pages hold 2 jobs of the form
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		page("/api/v1/job?page=2", map[string]any{"id": 5, "formName": "demo"}, map[string]any{"id": 4, "formName": "other"}),
+		page("/api/v1/job?page=3", map[string]any{"id": 3, "formName": "demo"}),
+	})
+	if err != nil {
+		panic(err)
+	}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	got, _, err := ListJobs(errorHandler, *r, JobsFilter{Form: "demo", Limit: 2})
+	if err != nil {
+		t.Fatalf("ListJobs() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != 5 || got[1].ID != 3 {
+		t.Errorf("ListJobs() = %v, want jobs 5 and 3", got)
+	}
+}
+
+func TestListJobsFinishedBefore(t *testing.T) {
+	envelope := map[string]any{"status": "success", "message": "jobs loaded", "data": []any{
+		map[string]any{"id": 4, "status": "running", "start": "2024-01-01 10:00:00"},
+		map[string]any{"id": 3, "status": "success", "start": "2024-05-01 10:00:00", "end": "2024-05-01 10:05:00"},
+		map[string]any{"id": 2, "status": "failed", "start": "2024-01-01 10:00:00", "end": "2024-01-01 10:05:00"},
+		map[string]any{"id": 1, "status": "success", "start": "2024-01-01 09:00:00"},
+	}}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		{ExpectedMethod: "GET", ExpectedURL: "job", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{envelope}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	got, err := ListJobsFinishedBefore(errorHandler, *r, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ListJobsFinishedBefore() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("ListJobsFinishedBefore() = %#v, want job 2", got)
+	}
+}
+
+func TestFindRecentDuplicateJob(t *testing.T) {
+	recent := func(age time.Duration) string {
+		return time.Now().Add(-age).UTC().Format(time.RFC3339)
+	}
+	envelope := map[string]any{"status": "success", "message": "jobs loaded", "data": []any{
+		map[string]any{"id": 1, "formName": "demo", "status": "success", "start": recent(2 * time.Hour), "extravars": `{"size": 10}`},
+		map[string]any{"id": 4, "formName": "demo", "status": "failed", "start": recent(time.Minute), "extravars": `{"size": 10}`},
+		map[string]any{"id": 3, "formName": "demo", "status": "success", "start": recent(2 * time.Minute), "extravars": `{"size": 20}`},
+		map[string]any{"id": 2, "formName": "demo", "status": "success", "start": recent(3 * time.Minute)},
+		map[string]any{"id": 5, "formName": "other", "status": "success", "start": recent(time.Minute), "extravars": `{"size": 10}`},
+	}}
+	// the list does not carry the extra vars of job 2
+	job2 := map[string]any{"status": "success", "message": "job found", "data": map[string]any{
+		"id": 2, "formName": "demo", "status": "success", "start": recent(3 * time.Minute), "extravars": `{"size": 10}`}}
+	// the record of job 2 does not carry its id
+	job2NoID := map[string]any{"status": "success", "message": "job found", "data": map[string]any{
+		"formName": "demo", "status": "success", "start": recent(3 * time.Minute), "extravars": `{"size": 10}`}}
+	tests := []struct {
+		name      string
+		extravars map[string]any
+		responses []restclient.MockResponse
+		wantID    int64
+	}{
+		{name: "read_job", extravars: map[string]any{"size": json.Number("10")}, responses: []restclient.MockResponse{
+			{ExpectedMethod: "GET", ExpectedURL: "job/2", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{job2}}},
+		}, wantID: 2},
+		{name: "read_job_without_id", extravars: map[string]any{"size": json.Number("10")}, responses: []restclient.MockResponse{
+			{ExpectedMethod: "GET", ExpectedURL: "job/2", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{job2NoID}}},
+		}, wantID: 2},
+		// job 2 was deleted after it was listed
+		{name: "deleted_job", extravars: map[string]any{"size": json.Number("10")}, responses: []restclient.MockResponse{
+			{ExpectedMethod: "GET", ExpectedURL: "job/2", StatusCode: 200, Response: restclient.RestResponse{}},
+		}},
+		{name: "from_list", extravars: map[string]any{"size": json.Number("20")}, wantID: 3},
+		// job 1 is out of the window, its extra vars are not compared
+		{name: "no_match", extravars: map[string]any{"size": json.Number("30")}, responses: []restclient.MockResponse{
+			{ExpectedMethod: "GET", ExpectedURL: "job/2", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{job2}}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			responses := append([]restclient.MockResponse{
+				{ExpectedMethod: "GET", ExpectedURL: "job", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{envelope}}},
+			}, tt.responses...)
+			r, err := restclient.NewMockedRestClient(responses)
+			if err != nil {
+				panic(err)
+			}
+			got, err := FindRecentDuplicateJob(errorHandler, *r, JobResourceModel{Form: "demo", Extravars: tt.extravars}, time.Hour)
+			if err != nil {
+				t.Fatalf("FindRecentDuplicateJob() error = %v", err)
+			}
+			if (got == nil) != (tt.wantID == 0) || (got != nil && got.ID != tt.wantID) {
+				t.Errorf("FindRecentDuplicateJob() = %v, want job %d", got, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestFindJobsByExtravar(t *testing.T) {
+	envelope := map[string]any{"status": "success", "message": "jobs loaded", "data": []any{
+		map[string]any{"id": 5, "status": "running", "start": "2024-05-03 10:00:00", "extravars": `{"correlation_id": "other"}`},
+		map[string]any{"id": 4, "status": "success", "start": "2024-05-02 10:00:00"},
+		map[string]any{"id": 3, "status": "failed", "start": "2024-05-01 10:00:00", "extravars": `{"correlation_id": "run-1", "size": 10}`},
+		map[string]any{"id": 2, "status": "success", "start": "2024-04-01 10:00:00", "extravars": `{"correlation_id": 12345678901234567890}`},
+		map[string]any{"id": 1, "status": "success", "start": "2024-03-01 10:00:00", "extravars": `not json`},
+	}}
+	// the list does not carry the extra vars of job 4
+	job4 := map[string]any{"status": "success", "message": "job found", "data": map[string]any{
+		"id": 4, "status": "success", "start": "2024-05-02 10:00:00", "extravars": `{"correlation_id": "run-1"}`}}
+	tests := []struct {
+		name    string
+		value   string
+		deleted bool
+		wantIDs []int64
+	}{
+		{name: "latest_first", value: "run-1", wantIDs: []int64{4, 3}},
+		{name: "number", value: "12345678901234567890", wantIDs: []int64{2}},
+		{name: "no_match", value: "run-2", wantIDs: []int64{}},
+		// job 4 was deleted after it was listed
+		{name: "deleted_job", value: "run-1", deleted: true, wantIDs: []int64{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			job4Response := restclient.RestResponse{NumRecords: 1, Records: []map[string]any{job4}}
+			if tt.deleted {
+				job4Response = restclient.RestResponse{}
+			}
+			r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+				{ExpectedMethod: "GET", ExpectedURL: "job", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{envelope}}},
+				{ExpectedMethod: "GET", ExpectedURL: "job/4", StatusCode: 200, Response: job4Response},
+			})
+			if err != nil {
+				panic(err)
+			}
+			got, err := FindJobsByExtravar(errorHandler, *r, "", "correlation_id", tt.value)
+			if err != nil {
+				t.Fatalf("FindJobsByExtravar() error = %v", err)
+			}
+			gotIDs := []int64{}
+			for _, job := range got {
+				gotIDs = append(gotIDs, job.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("FindJobsByExtravar() ids = %v, want %v", gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestFindJobsByExtravar_stopsReadingAfterMatch(t *testing.T) {
+	// the server lists the oldest job first, job 1 is not read as job 2 matches
+	envelope := map[string]any{"status": "success", "message": "jobs loaded", "data": []any{
+		map[string]any{"id": 1, "status": "success", "start": "2024-05-01 10:00:00"},
+		map[string]any{"id": 2, "status": "success", "start": "2024-05-02 10:00:00", "extravars": `{"correlation_id": "run-1"}`},
+	}}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		{ExpectedMethod: "GET", ExpectedURL: "job", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{envelope}}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	got, err := FindJobsByExtravar(errorHandler, *r, "", "correlation_id", "run-1")
+	if err != nil {
+		t.Fatalf("FindJobsByExtravar() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("FindJobsByExtravar() = %v, want job 2", got)
+	}
+}
+
+func TestSortJobs(t *testing.T) {
+	jobs := []JobGetDataSourceModel{
+		{ID: 3, Status: "success", Form: "b", Start: "2024-05-01 10:00:00", End: "2024-05-01 10:05:00"},
+		{ID: 1, Status: "running", Form: "a", Start: "2024-05-02 10:00:00"},
+		{ID: 2, Status: "success", Form: "a", Start: "2024-05-01 10:00:00", End: "2024-05-01 10:01:00"},
+	}
+	tests := []struct {
+		key     string
+		wantIDs []int64
+	}{
+		{key: "id", wantIDs: []int64{1, 2, 3}},
+		{key: "start", wantIDs: []int64{2, 3, 1}},
+		{key: "end", wantIDs: []int64{2, 3, 1}},
+		{key: "status", wantIDs: []int64{1, 2, 3}},
+		{key: "form_name", wantIDs: []int64{1, 2, 3}},
+		{key: JobSortServer, wantIDs: []int64{3, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			sorted := slices.Clone(jobs)
+			SortJobs(sorted, tt.key)
+			gotIDs := []int64{}
+			for _, job := range sorted {
+				gotIDs = append(gotIDs, job.ID)
+			}
+			if !reflect.DeepEqual(gotIDs, tt.wantIDs) {
+				t.Errorf("SortJobs(%s) ids = %v, want %v", tt.key, gotIDs, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestJobOutputCursor_next(t *testing.T) {
+	var cursor jobOutputCursor
+	steps := []struct {
+		output string
+		final  bool
+		want   []string
+	}{
+		{output: "", want: nil},
+		{output: "PLAY [all]\nTASK [ping", want: []string{"PLAY [all]"}},
+		{output: "PLAY [all]\nTASK [ping]\nok: [host1]\n", want: []string{"TASK [ping]", "ok: [host1]"}},
+		{output: "PLAY [all]\nTASK [ping]\nok: [host1]\n", want: nil},
+		{output: "PLAY [all]\nTASK [ping]\nok: [host1]\nPLAY RECAP", final: true, want: []string{"PLAY RECAP"}},
+		{output: "trimmed\n", want: []string{"trimmed"}},
+	}
+	for i, step := range steps {
+		if got := cursor.next(step.output, step.final); !reflect.DeepEqual(got, step.want) {
+			t.Errorf("step %d: next() = %q, want %q", i, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package interfaces
+
+import (
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// dataResponse describes the {"status", "message", "data"} envelope of Ansible Forms responses.
+type dataResponse struct {
+	Status  string `mapstructure:"status"`
+	Message string `mapstructure:"message"`
+	Data    any    `mapstructure:"data"`
+}
+
+// getRecords returns the records of a list endpoint, reading all pages.
+// Ansible Forms wraps lists in a {"status", "message", "data"} envelope, which RestClient returns as a single record per page.
+// In that case the elements of data are returned as records.
+func getRecords(errorHandler *utils.ErrorHandler, r restclient.RestClient, baseURL string, query *restclient.RestQuery) ([]map[string]any, error) {
+	records, _, err := getRecordsWithTotal(errorHandler, r, baseURL, query, nil)
+
+	return records, err
+}
+
+// getRecordsWithTotal returns the records of a list endpoint as getRecords, and the total number of records reported by the server
+// in the total or record_count field of the envelope, -1 when it is not reported.  With several pages, the last page read is trusted.
+// enough, when not nil, is called with the records of each page, and the next pages are not read once it returns true.
+func getRecordsWithTotal(errorHandler *utils.ErrorHandler, r restclient.RestClient, baseURL string, query *restclient.RestQuery,
+	enough func(records []map[string]any) bool) ([]map[string]any, int64, error) {
+	total := int64(-1)
+	unwrapped := []map[string]any{}
+	var decodeErr error
+	statusCode, err := r.GetRecordPages(baseURL, query, nil, func(records []map[string]any) bool {
+		page := make([]map[string]any, 0, len(records))
+		for _, record := range records {
+			data, ok := record["data"].([]any)
+			if !ok {
+				page = append(page, record)
+				continue
+			}
+			if reported, ok := reportedTotal(record); ok {
+				total = reported
+			}
+			for _, element := range data {
+				dataRecord, ok := element.(map[string]any)
+				if !ok {
+					decodeErr = fmt.Errorf("expecting a list of objects, got %#v", data)
+					return false
+				}
+				page = append(page, dataRecord)
+			}
+		}
+		unwrapped = append(unwrapped, page...)
+
+		return enough == nil || !enough(page)
+	})
+	if err != nil {
+		return nil, -1, errorHandler.MakeAndReportError(fmt.Sprintf("error reading %s", baseURL), fmt.Sprintf("error on GET %s: %s, statusCode %d", baseURL, err, statusCode))
+	}
+	if decodeErr != nil {
+		return nil, -1, errorHandler.MakeAndReportError(fmt.Sprintf("failed to decode response from GET %s", baseURL), decodeErr.Error())
+	}
+
+	return restclient.UniqueRecords(unwrapped), total, nil
+}
+
+// reportedTotal reads the total or record_count field of an envelope.
+func reportedTotal(envelope map[string]any) (int64, bool) {
+	for _, key := range []string{"total", "record_count"} {
+		value, ok := envelope[key]
+		if !ok {
+			continue
+		}
+		var total int64
+		if err := mapstructure.WeakDecode(value, &total); err == nil && total >= 0 {
+			return total, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,134 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mitchellh/mapstructure"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// ScheduleResourceModel describes the schedule sent to Ansible Forms.
+type ScheduleResourceModel struct {
+	Form      string         `mapstructure:"form"`
+	Cron      string         `mapstructure:"cron"`
+	Extravars map[string]any `mapstructure:"extravars,omitempty"`
+	Enabled   bool           `mapstructure:"enabled"`
+}
+
+// ScheduleGetDataSourceModel describes a schedule read from Ansible Forms.
+// Extravars are returned either as an object or as a JSON string.
+type ScheduleGetDataSourceModel struct {
+	ID        int64  `mapstructure:"id"`
+	Form      string `mapstructure:"form"`
+	Cron      string `mapstructure:"cron"`
+	Extravars any    `mapstructure:"extravars"`
+	Enabled   bool   `mapstructure:"enabled"`
+}
+
+// ExtravarsJSON returns the extravars of a schedule as a JSON string, empty when there are none.
+func (s *ScheduleGetDataSourceModel) ExtravarsJSON() (string, error) {
+	switch extravars := s.Extravars.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return extravars, nil
+	default:
+		bytes, err := json.Marshal(extravars)
+		if err != nil {
+			return "", err
+		}
+		return string(bytes), nil
+	}
+}
+
+// GetScheduleByID gets schedule info by id, nil when the schedule does not exist.
+func GetScheduleByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) (*ScheduleGetDataSourceModel, error) {
+	statusCode, response, err := r.GetNilOrOneRecord("schedule/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorHandler.MakeAndReportError("error reading schedule info", fmt.Sprintf("error on GET schedule/%s: %s, statusCode %d", id, err, statusCode))
+	}
+	if response == nil {
+		return nil, nil
+	}
+
+	var apiResp dataResponse
+	if err = mapstructure.Decode(response, &apiResp); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET schedule", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, restclient.Redact(response)))
+	}
+	if apiResp.Data == nil {
+		return nil, nil
+	}
+	var schedule ScheduleGetDataSourceModel
+	if err = mapstructure.WeakDecode(apiResp.Data, &schedule); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET schedule", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, restclient.Redact(response)))
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read schedule info: %#v", schedule))
+
+	return &schedule, nil
+}
+
+// CreateSchedule creates a schedule, and returns its id.
+func CreateSchedule(errorHandler *utils.ErrorHandler, r restclient.RestClient, data ScheduleResourceModel) (int64, error) {
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return 0, errorHandler.MakeAndReportError("error encoding schedule body", fmt.Sprintf("error on encoding POST schedule/ body: %s", err))
+	}
+
+	statusCode, response, err := r.CallCreateMethod("schedule/", nil, body)
+	if err != nil {
+		return 0, errorHandler.MakeAndReportError("error creating schedule", fmt.Sprintf("error on POST schedule/: %s, statusCode %d", err, statusCode))
+	}
+	if len(response.Records) == 0 {
+		return 0, errorHandler.MakeAndReportError("error creating schedule", fmt.Sprintf("no record returned by POST schedule/, statusCode %d", statusCode))
+	}
+
+	var apiResp dataResponse
+	if err = mapstructure.Decode(response.Records[0], &apiResp); err != nil {
+		return 0, errorHandler.MakeAndReportError("failed to decode response from POST schedule/", fmt.Sprintf("error: %s, statusCode %d", err, statusCode))
+	}
+	if apiResp.Status == "error" {
+		return 0, errorHandler.MakeAndReportError("error creating schedule", fmt.Sprintf("error on POST schedule/: %s, statusCode %d", apiResp.Message, statusCode))
+	}
+	id, ok := createdID(apiResp.Data)
+	if !ok {
+		return 0, errorHandler.MakeAndReportError("error creating schedule", fmt.Sprintf("no id returned by POST schedule/, statusCode %d, data %#v", statusCode, apiResp.Data))
+	}
+
+	return id, nil
+}
+
+// UpdateSchedule replaces a schedule, including its enabled state.
+func UpdateSchedule(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, data ScheduleResourceModel) error {
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return errorHandler.MakeAndReportError("error encoding schedule body", fmt.Sprintf("error on encoding PUT schedule/%s body: %s", id, err))
+	}
+
+	statusCode, _, err := r.CallReplaceMethod("schedule/"+id, nil, body)
+	if err != nil {
+		return errorHandler.MakeAndReportError("error updating schedule", fmt.Sprintf("error on PUT schedule/%s: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
+
+// DeleteScheduleByID deletes a schedule by ID.  A schedule that no longer exists is not an error.
+func DeleteScheduleByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) error {
+	statusCode, _, err := r.CallDeleteMethod("schedule/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return errorHandler.MakeAndReportError("error deleting schedule", fmt.Sprintf("error on DELETE schedule/%s: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestGetScheduleByID(t *testing.T) {
+	record := map[string]any{"status": "success", "message": "schedule found", "data": map[string]any{"id": float64(2), "form": "Demo Form", "cron": "0 2 * * *", "extravars": map[string]any{"region": "eu"}, "enabled": float64(0)}}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		{ExpectedMethod: "GET", ExpectedURL: "schedule/2", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{record}}},
+		{ExpectedMethod: "GET", ExpectedURL: "schedule/3", StatusCode: 404, Response: restclient.RestResponse{}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	got, err := GetScheduleByID(errorHandler, *r, "2")
+	if err != nil {
+		t.Fatalf("GetScheduleByID() error = %v", err)
+	}
+	if got.ID != 2 || got.Form != "Demo Form" || got.Cron != "0 2 * * *" || got.Enabled {
+		t.Errorf("GetScheduleByID() = %#v", got)
+	}
+	extravars, err := got.ExtravarsJSON()
+	if err != nil || extravars != `{"region":"eu"}` {
+		t.Errorf("ExtravarsJSON() = %q, %v, want %q", extravars, err, `{"region":"eu"}`)
+	}
+
+	got, err = GetScheduleByID(errorHandler, *r, "3")
+	if err != nil || got != nil {
+		t.Errorf("GetScheduleByID() = %#v, %v, want nil for a deleted schedule", got, err)
+	}
+}
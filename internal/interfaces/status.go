@@ -0,0 +1,73 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// ServerStatus describes whether an Ansible Forms server is reachable and accepts the credentials.
+type ServerStatus struct {
+	Reachable     bool
+	Authenticated bool
+	Version       string
+}
+
+// GetServerStatus checks connectivity with GET version, and the credentials with GET profile.
+// An error is reported when no HTTP response is received.  Rejected credentials are not an error, Authenticated is false.
+func GetServerStatus(errorHandler *utils.ErrorHandler, r restclient.RestClient) (*ServerStatus, error) {
+	statusCode, response, err := r.GetNilOrOneRecord("version", nil, nil)
+	if statusCode <= 0 {
+		return nil, errorHandler.MakeAndReportError("unable to reach Ansible Forms", fmt.Sprintf("error on GET version: %s", err))
+	}
+	status := ServerStatus{Reachable: true}
+	if err == nil {
+		status.Version = versionFromRecord(response)
+	} else {
+		tflog.Debug(errorHandler.Ctx, fmt.Sprintf("unable to read version: %s, statusCode %d", err, statusCode))
+	}
+
+	statusCode, _, err = r.GetNilOrOneRecord("profile", nil, nil)
+	switch {
+	case statusCode <= 0:
+		return nil, errorHandler.MakeAndReportError("unable to reach Ansible Forms", fmt.Sprintf("error on GET profile: %s", err))
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		tflog.Debug(errorHandler.Ctx, fmt.Sprintf("credentials rejected, statusCode %d", statusCode))
+	case err != nil:
+		return nil, errorHandler.MakeAndReportError("error reading profile", fmt.Sprintf("error on GET profile: %s, statusCode %d", err, statusCode))
+	default:
+		status.Authenticated = true
+	}
+
+	return &status, nil
+}
+
+// GetServerVersion returns the Ansible Forms version, empty when the server does not report it.
+func GetServerVersion(errorHandler *utils.ErrorHandler, r restclient.RestClient) (string, error) {
+	statusCode, response, err := r.GetNilOrOneRecord("version", nil, nil)
+	if err != nil {
+		return "", errorHandler.MakeAndReportError("error reading version", fmt.Sprintf("error on GET version: %s, statusCode %d", err, statusCode))
+	}
+
+	return versionFromRecord(response), nil
+}
+
+// versionFromRecord returns the version from a {"status", "message", "data"} envelope, data being the version or an object with a version.
+func versionFromRecord(record map[string]any) string {
+	value := record["data"]
+	if data, ok := value.(map[string]any); ok {
+		value = data["version"]
+	}
+	if value == nil {
+		value = record["version"]
+	}
+	if version, ok := value.(string); ok {
+		return version
+	}
+
+	return ""
+}
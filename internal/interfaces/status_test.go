@@ -0,0 +1,84 @@
+package interfaces
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestGetServerStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		profileStatus int
+		closed        bool
+		want          *ServerStatus
+		wantErr       bool
+	}{
+		{name: "authenticated", profileStatus: http.StatusOK, want: &ServerStatus{Reachable: true, Authenticated: true, Version: "5.0.1"}},
+		{name: "unauthenticated", profileStatus: http.StatusUnauthorized, want: &ServerStatus{Reachable: true, Version: "5.0.1"}},
+		{name: "unreachable", closed: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/api/v1/version":
+					_, _ = w.Write([]byte(`{"status": "success", "message": "", "data": "5.0.1"}`))
+				case "/api/v1/profile":
+					w.WriteHeader(tt.profileStatus)
+					_, _ = w.Write([]byte(`{"status": "success", "message": "", "data": {"username": "admin"}}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			cxProfile := restclient.ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+			if tt.closed {
+				server.Close()
+			} else {
+				defer server.Close()
+			}
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			r, err := restclient.NewClient(context.Background(), cxProfile, "resource/version", 600)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			got, err := GetServerStatus(errorHandler, *r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetServerStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetServerStatus() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionFromRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		record map[string]any
+		want   string
+	}{
+		{name: "data", record: map[string]any{"data": "5.0.1"}, want: "5.0.1"},
+		{name: "data_version", record: map[string]any{"data": map[string]any{"version": "5.0.2"}}, want: "5.0.2"},
+		{name: "version", record: map[string]any{"version": "5.0.3"}, want: "5.0.3"},
+		{name: "missing", record: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := versionFromRecord(tt.record); got != tt.want {
+				t.Errorf("versionFromRecord() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
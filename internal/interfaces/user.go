@@ -0,0 +1,115 @@
+package interfaces
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mitchellh/mapstructure"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// UserResourceModel describes the local user sent to Ansible Forms.
+type UserResourceModel struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password,omitempty"`
+	Email    string `mapstructure:"email"`
+	Role     string `mapstructure:"role,omitempty"`
+}
+
+// UserGetDataSourceModel describes a local user read from Ansible Forms.  The password is never returned.
+type UserGetDataSourceModel struct {
+	ID       int64  `mapstructure:"id"`
+	Username string `mapstructure:"username"`
+	Email    string `mapstructure:"email"`
+	Role     string `mapstructure:"role"`
+}
+
+// GetUserByID gets user info by id, nil when the user does not exist.
+func GetUserByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) (*UserGetDataSourceModel, error) {
+	statusCode, response, err := r.GetNilOrOneRecord("user/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorHandler.MakeAndReportError("error reading user info", fmt.Sprintf("error on GET user/%s: %s, statusCode %d", id, err, statusCode))
+	}
+	if response == nil {
+		return nil, nil
+	}
+
+	var apiResp dataResponse
+	if err = mapstructure.Decode(response, &apiResp); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET user", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, restclient.Redact(response)))
+	}
+	if apiResp.Data == nil {
+		return nil, nil
+	}
+	var user UserGetDataSourceModel
+	if err = mapstructure.WeakDecode(apiResp.Data, &user); err != nil {
+		return nil, errorHandler.MakeAndReportError("failed to decode response from GET user", fmt.Sprintf("error: %s, statusCode %d, response %#v", err, statusCode, restclient.Redact(response)))
+	}
+	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("read user info: %#v", user))
+
+	return &user, nil
+}
+
+// CreateUser creates a local user, and returns its id.
+func CreateUser(errorHandler *utils.ErrorHandler, r restclient.RestClient, data UserResourceModel) (int64, error) {
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return 0, errorHandler.MakeAndReportError("error encoding user body", fmt.Sprintf("error on encoding POST user/ body: %s", err))
+	}
+
+	statusCode, response, err := r.CallCreateMethod("user/", nil, body)
+	if err != nil {
+		return 0, errorHandler.MakeAndReportError("error creating user", fmt.Sprintf("error on POST user/: %s, statusCode %d", err, statusCode))
+	}
+	if len(response.Records) == 0 {
+		return 0, errorHandler.MakeAndReportError("error creating user", fmt.Sprintf("no record returned by POST user/, statusCode %d", statusCode))
+	}
+
+	var apiResp dataResponse
+	if err = mapstructure.Decode(response.Records[0], &apiResp); err != nil {
+		return 0, errorHandler.MakeAndReportError("failed to decode response from POST user/", fmt.Sprintf("error: %s, statusCode %d", err, statusCode))
+	}
+	if apiResp.Status == "error" {
+		return 0, errorHandler.MakeAndReportError("error creating user", fmt.Sprintf("error on POST user/: %s, statusCode %d", apiResp.Message, statusCode))
+	}
+	id, ok := createdID(apiResp.Data)
+	if !ok {
+		return 0, errorHandler.MakeAndReportError("error creating user", fmt.Sprintf("no id returned by POST user/, statusCode %d, data %#v", statusCode, apiResp.Data))
+	}
+
+	return id, nil
+}
+
+// UpdateUser replaces a local user.  The password is only changed when set.
+func UpdateUser(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string, data UserResourceModel) error {
+	var body map[string]any
+	if err := mapstructure.Decode(data, &body); err != nil {
+		return errorHandler.MakeAndReportError("error encoding user body", fmt.Sprintf("error on encoding PUT user/%s body: %s", id, err))
+	}
+
+	statusCode, _, err := r.CallReplaceMethod("user/"+id, nil, body)
+	if err != nil {
+		return errorHandler.MakeAndReportError("error updating user", fmt.Sprintf("error on PUT user/%s: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
+
+// DeleteUserByID deletes a local user by ID.  A user that no longer exists is not an error.
+func DeleteUserByID(errorHandler *utils.ErrorHandler, r restclient.RestClient, id string) error {
+	statusCode, _, err := r.CallDeleteMethod("user/"+id, nil, nil)
+	if statusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return errorHandler.MakeAndReportError("error deleting user", fmt.Sprintf("error on DELETE user/%s: %s, statusCode %d", id, err, statusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestGetUserByID(t *testing.T) {
+	record := map[string]any{"status": "success", "message": "user found", "data": map[string]any{"id": "5", "username": "alice", "email": "alice@example.com", "role": "operator"}}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{
+		{ExpectedMethod: "GET", ExpectedURL: "user/5", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{record}}},
+		{ExpectedMethod: "GET", ExpectedURL: "user/6", StatusCode: 404, Response: restclient.RestResponse{}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	got, err := GetUserByID(errorHandler, *r, "5")
+	if err != nil {
+		t.Fatalf("GetUserByID() error = %v", err)
+	}
+	want := &UserGetDataSourceModel{ID: 5, Username: "alice", Email: "alice@example.com", Role: "operator"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetUserByID() = %#v, want %#v", got, want)
+	}
+
+	got, err = GetUserByID(errorHandler, *r, "6")
+	if err != nil || got != nil {
+		t.Errorf("GetUserByID() = %#v, %v, want nil for a missing user", got, err)
+	}
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &CategoriesDataSource{}
+
+// CategoriesDataSource defines the data source implementation.
+type CategoriesDataSource struct {
+	config resourceOrDataSourceConfig
+}
+
+// NewCategoriesDataSource is a helper function to simplify the provider implementation.
+func NewCategoriesDataSource() datasource.DataSource {
+	return &CategoriesDataSource{
+		config: resourceOrDataSourceConfig{
+			name:       "categories_data_source",
+			dataSource: true,
+		},
+	}
+}
+
+// CategoriesDataSourceModel maps the data source schema data.
+type CategoriesDataSourceModel struct {
+	CxProfileName types.String                        `tfsdk:"cx_profile_name"`
+	Categories    []CategoriesDataSourceCategoryModel `tfsdk:"categories"`
+}
+
+// CategoriesDataSourceCategoryModel maps a category in the list.
+type CategoriesDataSourceCategoryModel struct {
+	Name      types.String `tfsdk:"name"`
+	FormCount types.Int64  `tfsdk:"form_count"`
+}
+
+// Metadata returns the data source type name.
+func (d *CategoriesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.config.name
+}
+
+// Schema defines the schema for the data source.
+func (d *CategoriesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Categories data source lists the categories of the forms of Ansible Forms, with the number of forms in each category. " +
+			"Ansible Forms has no category endpoint, so categories without forms are not listed, and categories have no description.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name. When unset, the provider data_source_connection_profile is used, else default_connection_profile, " +
+					"else the only connection profile, or the profile named `default` when several are defined.",
+				Optional: true,
+			},
+			"categories": schema.ListNestedAttribute{
+				MarkdownDescription: "Categories in the order they first appear in the forms, empty when no form has a category.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Category name.",
+							Computed:            true,
+						},
+						"form_count": schema.Int64Attribute{
+							MarkdownDescription: "Number of forms in the category.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *CategoriesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Categories Data Source Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	d.config.providerConfig = config
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *CategoriesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := d.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data CategoriesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	// we need to defer setting the client until we can read the connection profile name
+	client, err := getRestClient(errorHandler, d.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	restInfo, err := interfaces.ListCategories(errorHandler, *client)
+	if err != nil {
+		// error reporting done inside ListCategories
+		return
+	}
+
+	data.Categories = make([]CategoriesDataSourceCategoryModel, len(restInfo))
+	for index, category := range restInfo {
+		data.Categories[index] = CategoriesDataSourceCategoryModel{
+			Name:      types.StringValue(category.Name),
+			FormCount: types.Int64Value(category.FormCount),
+		}
+	}
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Debug(ctx, fmt.Sprintf("read a data source: %#v", data))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
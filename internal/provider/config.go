@@ -1,9 +1,16 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/mitchellh/mapstructure"
 	"golang.org/x/exp/maps"
@@ -14,13 +21,39 @@ import (
 
 // ConnectionProfile describes how to reach a cluster or svm
 type ConnectionProfile struct {
-	// TODO: add certs in addition to basic authentication
 	// TODO: Add Timeout (currently hardcoded to 10 seconds)
 	Hostname              string
+	Port                  int
+	Scheme                string
 	Username              string
 	Password              string
+	Token                 string
 	ValidateCerts         bool
+	CACert                string
+	ClientCert            string
+	ClientKey             string
+	MinTLSVersion         uint16
+	ProxyURL              string
 	MaxConcurrentRequests int
+	Headers               map[string]string
+	DisableRedirects      bool
+	TLSServerName         string
+	CompressRequests      bool
+	NoProxy               []string
+	name                  string
+}
+
+// GoString masks credentials, so that a profile can be logged with %#v.
+func (p ConnectionProfile) GoString() string {
+	type profile ConnectionProfile
+	masked := profile(p)
+	masked.Password = restclient.Mask(p.Password)
+	masked.Token = restclient.Mask(p.Token)
+	masked.ClientKey = restclient.Mask(p.ClientKey)
+	masked.ProxyURL = restclient.RedactURL(p.ProxyURL)
+	masked.Headers = restclient.RedactHeaders(p.Headers)
+
+	return fmt.Sprintf("%#v", masked)
 }
 
 // Config is created by the provide configure method
@@ -28,10 +61,77 @@ type Config struct {
 	ConnectionProfiles   map[string]ConnectionProfile
 	Version              string
 	JobCompletionTimeOut int
+	// MaxRetries, RetryWaitMin, and RetryWaitMax (in seconds) define the retry policy of REST clients
+	MaxRetries   int
+	RetryWaitMin int
+	RetryWaitMax int
+	// RequestTimeout bounds each HTTP request, in seconds
+	RequestTimeout int
+	// DefaultConnectionProfile names the profile used when a resource does not set one
+	DefaultConnectionProfile string
+	// MaxIdleConns and IdleConnTimeout (in seconds) tune the connection pool of each profile, 0 uses the defaults
+	MaxIdleConns    int
+	IdleConnTimeout int
+	// StreamJobOutput logs the job output while waiting for a job to complete
+	StreamJobOutput bool
+	// LogTimings logs the timing of each request at Info level rather than Debug level
+	LogTimings bool
+	// UserAgentSuffix is appended to the User-Agent header, to tell pipelines apart
+	UserAgentSuffix string
+	// DataSourceConnectionProfile names the profile used by data sources that do not set one, e.g. a read-only account
+	DataSourceConnectionProfile string
+	// serverVersions holds the Ansible Forms version of each profile, read once when a client is first built for it
+	serverVersions map[string]*serverVersion
+	// requestSlots limits the number of concurrent requests for each profile with MaxConcurrentRequests set
+	requestSlots map[string]chan int
+	// OperationTimeout (in seconds) and OperationDeadline bound the activity of the provider from Configure, zero when not set
+	OperationTimeout  int
+	OperationDeadline time.Time
+	// JobURLTemplate is the path of a job in the Ansible Forms web UI, {id} is replaced with the job id
+	JobURLTemplate string
+	// JobPollMaxTransientErrors is the number of consecutive failed polls tolerated while waiting for a job
+	JobPollMaxTransientErrors int
+	// PollStrategy is how jobs are polled while waiting for them, pollStrategyInterval or pollStrategyLongPoll
+	PollStrategy string
+	// ResponseHeadersAllowlist names the response headers captured by REST clients, none when empty
+	ResponseHeadersAllowlist []string
+}
+
+// poll_strategy values.
+const (
+	pollStrategyInterval = "interval"
+	pollStrategyLongPoll = "long_poll"
+)
+
+// jobLongPollTimeout is the longest server-side timeout requested when poll_strategy is long_poll.
+const jobLongPollTimeout = 30 * time.Second
+
+// defaultJobURLTemplate is the path of a job in the Ansible Forms web UI, used when job_url_template is not set
+const defaultJobURLTemplate = "/#/output/{id}"
+
+// withOperationDeadline returns ctx bounded by the operation deadline, when operation_timeout is set.
+// The returned function releases the context and reports an error when the deadline was hit, call it when the operation is done.
+func (c Config) withOperationDeadline(ctx context.Context, diags *diag.Diagnostics) (context.Context, func()) {
+	if c.OperationDeadline.IsZero() {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithDeadline(ctx, c.OperationDeadline)
+
+	return ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !time.Now().Before(c.OperationDeadline) {
+			diags.AddError("operation_timeout exceeded",
+				fmt.Sprintf("The provider operation_timeout of %d seconds was reached at %s, pending requests and job polling were aborted.",
+					c.OperationTimeout, c.OperationDeadline.Format(time.RFC3339)))
+		}
+		cancel()
+	}
 }
 
+// defaultConnectionProfileName is the profile used when no name is given and several profiles are defined
+const defaultConnectionProfileName = "default"
+
 // GetConnectionProfile retrieves a connection profile based on name
-// If name is empty and only one profile is defined, it is returned
+// If name is empty, DefaultConnectionProfile is returned when set, else the only profile when one profile is defined, or the profile named default
 func (c *Config) GetConnectionProfile(name string) (*ConnectionProfile, error) {
 	if c == nil {
 		return nil, fmt.Errorf("internal error, config is not initialized")
@@ -39,20 +139,103 @@ func (c *Config) GetConnectionProfile(name string) (*ConnectionProfile, error) {
 	if len(c.ConnectionProfiles) == 0 {
 		return nil, fmt.Errorf("error, at least one connection profile is required to connect to ONTAP")
 	}
+	if name == "" {
+		name = c.DefaultConnectionProfile
+	}
 	if name == "" && len(c.ConnectionProfiles) == 1 {
 		name = maps.Keys(c.ConnectionProfiles)[0]
 	}
+	if _, ok := c.ConnectionProfiles[defaultConnectionProfileName]; name == "" && ok {
+		name = defaultConnectionProfileName
+	}
 	if name == "" {
-		return nil, fmt.Errorf("error, connection profile name is required if more than one profile is defined")
+		return nil, fmt.Errorf("error, connection profile name is required if more than one profile is defined and none is named %s", defaultConnectionProfileName)
 	}
 	if profile, ok := c.ConnectionProfiles[name]; ok {
+		profile.name = name
 		return &profile, nil
 	}
 	return nil, fmt.Errorf("connection profile with name %s is not defined", name)
 }
 
+// serverVersion is the Ansible Forms version of a connection profile, read once.
+type serverVersion struct {
+	once    sync.Once
+	version string
+}
+
+// ServerVersion returns the Ansible Forms version of the profile identified by cxProfileName, empty when it could not be read.
+// The version is read when a client is first built for the profile, or on the first call. Resources may use it to adapt payloads to older servers.
+func (c *Config) ServerVersion(errorHandler *utils.ErrorHandler, cxProfileName string) string {
+	probe := c.checkServerVersion(errorHandler, cxProfileName)
+	if probe == nil {
+		return ""
+	}
+
+	return probe.version
+}
+
+// longPollTimeout returns the server-side timeout of the job wait requests, half of the request timeout up to jobLongPollTimeout,
+// or 0 to poll at intervals.
+func (c *Config) longPollTimeout() time.Duration {
+	if c.PollStrategy != pollStrategyLongPoll || c.RequestTimeout <= 0 {
+		return 0
+	}
+	timeout := time.Duration(c.RequestTimeout) * time.Second / 2
+	if timeout > jobLongPollTimeout {
+		timeout = jobLongPollTimeout
+	}
+	return timeout
+}
+
+// JobURL returns the link to job id in the Ansible Forms web UI of the profile identified by cxProfileName, empty when the profile is unknown.
+func (c *Config) JobURL(cxProfileName string, id string) string {
+	connectionProfile, err := c.GetConnectionProfile(cxProfileName)
+	if err != nil {
+		return ""
+	}
+	scheme := connectionProfile.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	host := connectionProfile.Hostname
+	if connectionProfile.Port != 0 {
+		host = net.JoinHostPort(host, strconv.Itoa(connectionProfile.Port))
+	}
+	template := c.JobURLTemplate
+	if template == "" {
+		template = defaultJobURLTemplate
+	}
+	if !strings.HasPrefix(template, "/") {
+		template = "/" + template
+	}
+
+	return scheme + "://" + host + strings.ReplaceAll(template, "{id}", id)
+}
+
+// userAgent returns the User-Agent header sent to Ansible Forms, terraform-provider-ansible-forms/<version> followed by the suffix when set.
+func (c *Config) userAgent() string {
+	userAgent := "terraform-provider-ansible-forms/" + c.Version
+	if c.UserAgentSuffix != "" {
+		userAgent += " " + c.UserAgentSuffix
+	}
+
+	return userAgent
+}
+
 // NewClient creates a RestClient based on the connection profile identified by cxProfileName
 func (c *Config) NewClient(errorHandler *utils.ErrorHandler, cxProfileName string, resName string) (*restclient.RestClient, error) {
+	client, err := c.newClient(errorHandler, cxProfileName, resName)
+	if err != nil {
+		return nil, err
+	}
+	c.checkServerVersion(errorHandler, cxProfileName)
+
+	return client, nil
+}
+
+// newClient creates a REST client for a connection profile, as NewClient, without checking the version of the server.
+func (c *Config) newClient(errorHandler *utils.ErrorHandler, cxProfileName string, resName string) (*restclient.RestClient, error) {
 	connectionProfile, err := c.GetConnectionProfile(cxProfileName)
 	if err != nil {
 		return nil, errorHandler.MakeAndReportError("failed to set connection profile", err.Error())
@@ -63,6 +246,8 @@ func (c *Config) NewClient(errorHandler *utils.ErrorHandler, cxProfileName strin
 		return nil, errorHandler.MakeAndReportError("unable to create REST client",
 			fmt.Sprintf("decode error on ConnectionProfile %#v to restclient.ConnectionProfile", connectionProfile))
 	}
+	profile.MaxIdleConns = c.MaxIdleConns
+	profile.IdleConnTimeout = time.Duration(c.IdleConnTimeout) * time.Second
 	// the tag resource_name/version will be used for telemetry
 
 	tflog.Debug(errorHandler.Ctx, fmt.Sprintf("Version string is: %#v", strings.Join([]string{"TerrafromONTAP", resName, c.Version}, "/")))
@@ -71,5 +256,19 @@ func (c *Config) NewClient(errorHandler *utils.ErrorHandler, cxProfileName strin
 		return nil, errorHandler.MakeAndReportError("unable to create REST client",
 			fmt.Sprintf("error creating REST client: %s", err))
 	}
+	if slots, ok := c.requestSlots[connectionProfile.name]; ok {
+		client.SetRequestSlots(slots)
+	}
+	client.SetRequestTimeout(time.Duration(c.RequestTimeout) * time.Second)
+	client.SetUserAgent(c.userAgent())
+	client.SetLogTimings(c.LogTimings)
+	if len(c.ResponseHeadersAllowlist) > 0 {
+		client.CaptureResponseHeaders(c.ResponseHeadersAllowlist)
+	}
+	client.SetRetryPolicy(restclient.RetryPolicy{
+		MaxRetries: c.MaxRetries,
+		WaitMin:    time.Duration(c.RetryWaitMin) * time.Second,
+		WaitMax:    time.Duration(c.RetryWaitMax) * time.Second,
+	})
 	return client, err
 }
@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &CredentialResource{}
+	_ resource.ResourceWithConfigure   = &CredentialResource{}
+	_ resource.ResourceWithImportState = &CredentialResource{}
+)
+
+// NewCredentialResource is a helper function to simplify the provider implementation.
+func NewCredentialResource() resource.Resource {
+	return &CredentialResource{
+		config: resourceOrDataSourceConfig{
+			name: "credential_resource",
+		},
+	}
+}
+
+// CredentialResource is the resource implementation.
+type CredentialResource struct {
+	config resourceOrDataSourceConfig
+}
+
+// CredentialResourceModel maps the resource schema data.
+type CredentialResourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Type          types.String `tfsdk:"type"`
+	Username      types.String `tfsdk:"username"`
+	Password      types.String `tfsdk:"password"`
+	Description   types.String `tfsdk:"description"`
+}
+
+// Metadata returns the resource type name.
+func (r *CredentialResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.config.name
+}
+
+// Schema defines the schema for the resource.
+func (r *CredentialResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Credential resource manages a credential of Ansible Forms, used by forms to reach other systems.",
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Connection profile name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "ID of a credential.",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Name of a credential.",
+			},
+			"type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Database type, when the credential is used to query a database, e.g. mysql.",
+			},
+			"username": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "User name of a credential.",
+			},
+			"password": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+				MarkdownDescription: "Password or secret of a credential. Ansible Forms never returns it, " +
+					"so changes made outside of Terraform are not detected, and it is not set on import.",
+			},
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Description of a credential.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *CredentialResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	r.config.providerConfig = config
+}
+
+// request converts the model to the credential sent to Ansible Forms.
+func (data *CredentialResourceModel) request() interfaces.CredentialResourceModel {
+	return interfaces.CredentialResourceModel{
+		Name:        data.Name.ValueString(),
+		User:        data.Username.ValueString(),
+		Password:    data.Password.ValueString(),
+		Description: data.Description.ValueString(),
+		DBType:      data.Type.ValueString(),
+	}
+}
+
+// Create a new resource.
+func (r *CredentialResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *CredentialResourceModel
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	id, err := interfaces.CreateCredential(errorHandler, *client, data.request())
+	if err != nil {
+		// error reporting done inside CreateCredential
+		return
+	}
+	data.ID = types.StringValue(strconv.FormatInt(id, 10))
+
+	tflog.Trace(ctx, "created a resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *CredentialResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *CredentialResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	credential, err := interfaces.GetCredentialByID(errorHandler, *client, data.ID.ValueString())
+	if err != nil {
+		return
+	}
+	if credential == nil {
+		tflog.Info(ctx, fmt.Sprintf("credential %s no longer exists, removing it from state", data.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// the password is never returned, keep the value from state.
+	data.Name = types.StringValue(credential.Name)
+	data.Type = stringValueOrNull(data.Type, credential.DBType)
+	data.Username = stringValueOrNull(data.Username, credential.User)
+	data.Description = stringValueOrNull(data.Description, credential.Description)
+
+	tflog.Debug(ctx, fmt.Sprintf("read a credential resource: %s", data.ID.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *CredentialResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var plan, state *CredentialResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, plan.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	if err = interfaces.UpdateCredential(errorHandler, *client, state.ID.ValueString(), plan.request()); err != nil {
+		return
+	}
+	plan.ID = state.ID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *CredentialResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *CredentialResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+	if err = interfaces.DeleteCredentialByID(errorHandler, *client, data.ID.ValueString()); err != nil {
+		return
+	}
+}
+
+// ImportState imports a credential, the import ID is <cx_profile_name>,<id>.
+func (r *CredentialResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	cxProfileName, id, found := strings.Cut(req.ID, ",")
+	if !found || cxProfileName == "" || id == "" {
+		resp.Diagnostics.AddError("invalid import ID",
+			fmt.Sprintf("Expected <cx_profile_name>,<id>, got %q.", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cx_profile_name"), cxProfileName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccCredentialResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCredentialResourceConfig("tf_acc_credential", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_credential_resource.credential", "name", "tf_acc_credential"),
+					resource.TestCheckResourceAttr("ansible-forms_credential_resource.credential", "username", "tf_acc_user"),
+					resource.TestCheckResourceAttr("ansible-forms_credential_resource.credential", "description", "first"),
+					resource.TestCheckResourceAttrSet("ansible-forms_credential_resource.credential", "id")),
+			},
+			{
+				Config: testAccCredentialResourceConfig("tf_acc_credential", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_credential_resource.credential", "description", "second")),
+			},
+			{
+				ResourceName: "ansible-forms_credential_resource.credential",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return "cluster4," + s.RootModule().Resources["ansible-forms_credential_resource.credential"].Primary.ID, nil
+				},
+				ImportStateVerify: true,
+				// the password is never returned by Ansible Forms
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func testAccCredentialResourceConfig(name string, description string) string {
+	// environment variables are checked in testAccPreCheck
+	host := os.Getenv("TF_ACC_ANSIBLE_FORMS_HOST")
+	admin := os.Getenv("TF_ACC_ANSIBLE_FORMS_USER")
+	password := os.Getenv("TF_ACC_ANSIBLE_FORMS_PASS")
+	return fmt.Sprintf(`
+provider "ansible-forms" {
+ connection_profiles = [
+    {
+      name = "cluster4"
+      hostname = "%s"
+      username = "%s"
+      password = "%s"
+      validate_certs = false
+    },
+  ]
+}
+
+resource "ansible-forms_credential_resource" "credential" {
+  cx_profile_name = "cluster4"
+  name            = "%s"
+  username        = "tf_acc_user"
+  password        = "tf_acc_password"
+  description     = "%s"
+}`, host, admin, password, name, description)
+}
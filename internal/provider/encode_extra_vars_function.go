@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ function.Function = &EncodeExtraVarsFunction{}
+)
+
+// NewEncodeExtraVarsFunction is a helper function to simplify the provider implementation.
+func NewEncodeExtraVarsFunction() function.Function {
+	return &EncodeExtraVarsFunction{}
+}
+
+// EncodeExtraVarsFunction encodes extra vars as JSON, as the job resource sends them when launching a job.
+type EncodeExtraVarsFunction struct{}
+
+// Metadata returns the function name.
+func (f *EncodeExtraVarsFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "encode_extra_vars"
+}
+
+// Definition defines the function parameters and return type.
+func (f *EncodeExtraVarsFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Encode extra vars as JSON",
+		Description: "Returns the JSON object sent as the extra vars of a job launched with these values, with sorted keys and numbers as written, " +
+			"e.g. to preview the extra vars of a job, or to set `extravars_json`. Nested objects, lists, booleans, numbers, and nulls are kept as is. " +
+			"As in the launch request, the characters <, >, and & in strings are escaped, e.g. as \\u003c.",
+		Parameters: []function.Parameter{
+			function.DynamicParameter{
+				Name:        "extra_vars",
+				Description: "Extra vars as an object or a map, e.g. `{ size = 10, tags = [\"a\"] }`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run encodes the extra vars.
+func (f *EncodeExtraVarsFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var extravars types.Dynamic
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &extravars))
+	if resp.Error != nil {
+		return
+	}
+
+	value, err := extravarValue(extravars)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	values, ok := value.(map[string]any)
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("extra vars must be an object or a map, got %s.", extravars.UnderlyingValue().Type(ctx)))
+		return
+	}
+	encoded, err := interfaces.EncodeExtravars(values)
+	if err != nil {
+		resp.Error = function.NewFuncError(fmt.Sprintf("unable to encode extra vars: %s.", err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, encoded))
+}
+
+// extravarValue converts a Terraform value to the value sent in the extra vars of a job, numbers are kept as written with json.Number.
+// Objects and maps are converted to maps, lists, sets, and tuples to slices, and null values to nil.
+func extravarValue(value attr.Value) (any, error) {
+	if value == nil || value.IsNull() {
+		return nil, nil
+	}
+	if value.IsUnknown() {
+		return nil, fmt.Errorf("extra vars must be known, got an unknown value")
+	}
+	var elements []attr.Value
+	var attributes map[string]attr.Value
+	isMap := false
+	switch v := value.(type) {
+	case types.Dynamic:
+		return extravarValue(v.UnderlyingValue())
+	case types.String:
+		return v.ValueString(), nil
+	case types.Bool:
+		return v.ValueBool(), nil
+	case types.Number:
+		return json.Number(v.ValueBigFloat().Text('f', -1)), nil
+	case types.Int64:
+		return v.ValueInt64(), nil
+	case types.Float64:
+		return v.ValueFloat64(), nil
+	case types.Object:
+		attributes, isMap = v.Attributes(), true
+	case types.Map:
+		attributes, isMap = v.Elements(), true
+	case types.List:
+		elements = v.Elements()
+	case types.Set:
+		elements = v.Elements()
+	case types.Tuple:
+		elements = v.Elements()
+	default:
+		return nil, fmt.Errorf("unsupported value of type %T", value)
+	}
+	if isMap {
+		result := make(map[string]any, len(attributes))
+		for name, attribute := range attributes {
+			converted, err := extravarValue(attribute)
+			if err != nil {
+				return nil, err
+			}
+			result[name] = converted
+		}
+		return result, nil
+	}
+	result := make([]any, 0, len(elements))
+	for _, element := range elements {
+		converted, err := extravarValue(element)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, converted)
+	}
+
+	return result, nil
+}
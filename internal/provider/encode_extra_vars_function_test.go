@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+func TestEncodeExtraVarsFunction(t *testing.T) {
+	largeNumber, _, _ := big.ParseFloat("12345678901234567890", 10, 512, big.ToNearestEven)
+	nested := types.ObjectValueMust(
+		map[string]attr.Type{"name": types.StringType, "enabled": types.BoolType, "ports": types.TupleType{ElemTypes: []attr.Type{types.NumberType, types.StringType}}},
+		map[string]attr.Value{
+			"name":    types.StringValue("<share>"),
+			"enabled": types.BoolValue(true),
+			"ports":   types.TupleValueMust([]attr.Type{types.NumberType, types.StringType}, []attr.Value{types.NumberValue(big.NewFloat(443)), types.StringValue("8443")}),
+		})
+	tests := []struct {
+		name    string
+		value   attr.Value
+		want    string
+		wantErr bool
+	}{
+		{name: "empty", value: types.ObjectValueMust(map[string]attr.Type{}, map[string]attr.Value{}), want: `{}`},
+		{name: "mixed", value: types.ObjectValueMust(
+			map[string]attr.Type{"size": types.NumberType, "ratio": types.NumberType, "id": types.NumberType, "region": types.StringType, "owner": types.StringType},
+			map[string]attr.Value{
+				"size":   types.NumberValue(big.NewFloat(10)),
+				"ratio":  types.NumberValue(big.NewFloat(0.5)),
+				"id":     types.NumberValue(largeNumber),
+				"region": types.StringValue("eu"),
+				"owner":  types.StringNull(),
+			}), want: `{"id":12345678901234567890,"owner":null,"ratio":0.5,"region":"eu","size":10}`},
+		// HTML characters are escaped, as in the launch request
+		{name: "nested", value: types.ObjectValueMust(
+			map[string]attr.Type{"share": nested.Type(context.Background()), "tags": types.ListType{ElemType: types.StringType}},
+			map[string]attr.Value{
+				"share": nested,
+				"tags":  types.ListValueMust(types.StringType, []attr.Value{types.StringValue("b"), types.StringValue("a")}),
+			}), want: `{"share":{"enabled":true,"name":"\u003cshare\u003e","ports":[443,"8443"]},"tags":["b","a"]}`},
+		{name: "map", value: types.MapValueMust(types.StringType, map[string]attr.Value{"b": types.StringValue("2"), "a": types.StringValue("1")}), want: `{"a":"1","b":"2"}`},
+		{name: "not_an_object", value: types.StringValue("size=10"), wantErr: true},
+		{name: "list", value: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("a")}), wantErr: true},
+	}
+	f := NewEncodeExtraVarsFunction()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{types.DynamicValue(tt.value)})}
+			resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+			f.Run(context.Background(), req, &resp)
+			if (resp.Error != nil) != tt.wantErr {
+				t.Fatalf("encode_extra_vars() error = %v, wantErr %v", resp.Error, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := resp.Result.Value(); !got.Equal(types.StringValue(tt.want)) {
+				t.Errorf("encode_extra_vars() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeExtraVarsFunction_matchesLaunchBody(t *testing.T) {
+	largeNumber, _, _ := big.ParseFloat("12345678901234567890", 10, 512, big.ToNearestEven)
+	tests := []struct {
+		name          string
+		value         attr.Value
+		extravarsJSON types.String
+	}{
+		{name: "empty", value: types.ObjectValueMust(map[string]attr.Type{}, map[string]attr.Value{}), extravarsJSON: types.StringNull()},
+		{name: "mixed", value: types.ObjectValueMust(
+			map[string]attr.Type{"size": types.NumberType, "id": types.NumberType, "region": types.StringType, "tags": types.ListType{ElemType: types.StringType}},
+			map[string]attr.Value{
+				"size":   types.NumberValue(big.NewFloat(10)),
+				"id":     types.NumberValue(largeNumber),
+				"region": types.StringValue("<eu>"),
+				"tags":   types.ListValueMust(types.StringType, []attr.Value{types.StringValue("b"), types.StringValue("a")}),
+			}), extravarsJSON: types.StringValue(`{"tags": ["b", "a"], "region": "<eu>", "size": 10, "id": 12345678901234567890}`)},
+	}
+	f := NewEncodeExtraVarsFunction()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{types.DynamicValue(tt.value)})}
+			resp := function.RunResponse{Result: function.NewResultData(types.StringUnknown())}
+			f.Run(context.Background(), req, &resp)
+			if resp.Error != nil {
+				t.Fatalf("encode_extra_vars() error = %v", resp.Error)
+			}
+
+			var sent map[string]json.RawMessage
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					t.Errorf("decoding launch body: %s", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"status": "success", "data": {"output": {"id": 1}}}`))
+			}))
+			defer server.Close()
+			config := Config{ConnectionProfiles: map[string]ConnectionProfile{"cluster1": {Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}}}
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			client, err := config.newClient(errorHandler, "", "test")
+			if err != nil {
+				t.Fatalf("newClient() error = %v", err)
+			}
+			extravars := expandExtravarsJSON(&diags, tt.extravarsJSON)
+			if _, err := interfaces.CreateJob(errorHandler, *client, interfaces.JobResourceModel{Form: "demo", Extravars: extravars}); err != nil {
+				t.Fatalf("CreateJob() error = %v, diagnostics = %v", err, diags)
+			}
+			if got := resp.Result.Value(); !got.Equal(types.StringValue(string(sent["extravars"]))) {
+				t.Errorf("encode_extra_vars() = %v, want the extravars of the launch body %s", got, sent["extravars"])
+			}
+		})
+	}
+}
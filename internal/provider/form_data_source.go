@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &FormDataSource{}
+
+// FormDataSource defines the data source implementation.
+type FormDataSource struct {
+	config resourceOrDataSourceConfig
+}
+
+// NewFormDataSource is a helper function to simplify the provider implementation.
+func NewFormDataSource() datasource.DataSource {
+	return &FormDataSource{
+		config: resourceOrDataSourceConfig{
+			name:       "form_data_source",
+			dataSource: true,
+		},
+	}
+}
+
+// FormDataSourceModel maps the data source schema data.
+type FormDataSourceModel struct {
+	CxProfileName  types.String               `tfsdk:"cx_profile_name"`
+	Name           types.String               `tfsdk:"name"`
+	Description    types.String               `tfsdk:"description"`
+	Categories     []types.String             `tfsdk:"categories"`
+	Fields         []FormFieldDataSourceModel `tfsdk:"fields"`
+	RequiredInputs []types.String             `tfsdk:"required_inputs"`
+}
+
+// FormFieldDataSourceModel maps a form field.
+type FormFieldDataSourceModel struct {
+	Name     types.String   `tfsdk:"name"`
+	Type     types.String   `tfsdk:"type"`
+	Label    types.String   `tfsdk:"label"`
+	Required types.Bool     `tfsdk:"required"`
+	Values   []types.String `tfsdk:"values"`
+}
+
+// Metadata returns the data source type name.
+func (d *FormDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.config.name
+}
+
+// Schema defines the schema for the data source.
+func (d *FormDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Form data source retrieves a form and its fields from Ansible Forms.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name",
+				Required:            true,
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "Form name.",
+				Required:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Description of a form.",
+				Computed:            true,
+			},
+			"categories": schema.ListAttribute{
+				MarkdownDescription: "Categories of a form.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"fields": schema.ListNestedAttribute{
+				MarkdownDescription: "Fields of a form.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Field name, used as extra var name.",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "Field type.",
+							Computed:            true,
+						},
+						"label": schema.StringAttribute{
+							MarkdownDescription: "Field label.",
+							Computed:            true,
+						},
+						"required": schema.BoolAttribute{
+							MarkdownDescription: "Whether a value is required.",
+							Computed:            true,
+						},
+						"values": schema.ListAttribute{
+							MarkdownDescription: "Allowed values, when the field is an enum.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"required_inputs": schema.ListAttribute{
+				MarkdownDescription: "Names of the required fields.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *FormDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Form Data Source Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	d.config.providerConfig = config
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *FormDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := d.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data FormDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	// we need to defer setting the client until we can read the connection profile name
+	client, err := getRestClient(errorHandler, d.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	restInfo, err := interfaces.GetFormByName(errorHandler, *client, data.Name.ValueString())
+	if err != nil {
+		// error reporting done inside GetFormByName
+		return
+	}
+
+	data.Description = types.StringValue(restInfo.Description)
+	data.Categories = flattenTypesStringList(restInfo.Categories)
+	data.Fields = make([]FormFieldDataSourceModel, len(restInfo.Fields))
+	for index, field := range restInfo.Fields {
+		values := make([]string, len(field.Values))
+		for i, value := range field.Values {
+			values[i] = fmt.Sprint(value)
+		}
+		data.Fields[index] = FormFieldDataSourceModel{
+			Name:     types.StringValue(field.Name),
+			Type:     types.StringValue(field.Type),
+			Label:    types.StringValue(field.Label),
+			Required: types.BoolValue(field.Required),
+			Values:   flattenTypesStringList(values),
+		}
+	}
+	data.RequiredInputs = flattenTypesStringList(restInfo.RequiredInputs())
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Debug(ctx, fmt.Sprintf("read a data source: %#v", data))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &FormsDataSource{}
+
+// FormsDataSource defines the data source implementation.
+type FormsDataSource struct {
+	config resourceOrDataSourceConfig
+}
+
+// NewFormsDataSource is a helper function to simplify the provider implementation.
+func NewFormsDataSource() datasource.DataSource {
+	return &FormsDataSource{
+		config: resourceOrDataSourceConfig{
+			name:       "forms_data_source",
+			dataSource: true,
+		},
+	}
+}
+
+// FormsDataSourceModel maps the data source schema data.
+type FormsDataSourceModel struct {
+	CxProfileName types.String               `tfsdk:"cx_profile_name"`
+	Category      types.String               `tfsdk:"category"`
+	Forms         []FormsDataSourceFormModel `tfsdk:"forms"`
+	// SortBy is the key forms are sorted by, name when null.
+	SortBy types.String `tfsdk:"sort_by"`
+}
+
+// FormsDataSourceFormModel maps a form in the list.
+type FormsDataSourceFormModel struct {
+	Name        types.String   `tfsdk:"name"`
+	Description types.String   `tfsdk:"description"`
+	Categories  []types.String `tfsdk:"categories"`
+}
+
+// Metadata returns the data source type name.
+func (d *FormsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.config.name
+}
+
+// Schema defines the schema for the data source.
+func (d *FormsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Forms data source lists the forms of Ansible Forms with their categories, optionally filtered by category. " +
+			"Forms are sorted by `sort_by`, by name by default, so that their order does not depend on the server.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name. When unset, the provider default_connection_profile is used, else the only connection profile, " +
+					"or the profile named `default` when several are defined.",
+				Optional: true,
+			},
+			"category": schema.StringAttribute{
+				MarkdownDescription: "Only list forms in this category.",
+				Optional:            true,
+			},
+			"sort_by": schema.StringAttribute{
+				MarkdownDescription: "Key `forms` are sorted by: `name`, in ascending order, or `server` to keep the order sent by the server, which may change between reads. " +
+					"Defaults to `name`, so that the order is deterministic and indexes into `forms` do not cause spurious diffs.",
+				Optional: true,
+			},
+			"forms": schema.ListNestedAttribute{
+				MarkdownDescription: "Forms matching the filter, sorted by `sort_by`, empty when there is none.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							MarkdownDescription: "Form name.",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "Description of a form.",
+							Computed:            true,
+						},
+						"categories": schema.ListAttribute{
+							MarkdownDescription: "Categories of a form, a form may be in several categories.",
+							ElementType:         types.StringType,
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *FormsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Forms Data Source Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	d.config.providerConfig = config
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *FormsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := d.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data FormsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	sortBy := data.SortBy.ValueString()
+	if data.SortBy.IsNull() {
+		sortBy = "name"
+	}
+	if !slices.Contains(interfaces.FormSortKeys, sortBy) {
+		resp.Diagnostics.AddAttributeError(path.Root("sort_by"), "invalid sort_by",
+			fmt.Sprintf("sort_by must be one of %s, got %q.", strings.Join(interfaces.FormSortKeys, ", "), sortBy))
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	// we need to defer setting the client until we can read the connection profile name
+	client, err := getRestClient(errorHandler, d.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	restInfo, err := interfaces.ListForms(errorHandler, *client, interfaces.FormsFilter{Category: data.Category.ValueString()})
+	if err != nil {
+		// error reporting done inside ListForms
+		return
+	}
+	interfaces.SortForms(restInfo, sortBy)
+
+	data.Forms = make([]FormsDataSourceFormModel, len(restInfo))
+	for index, form := range restInfo {
+		categories := []types.String{}
+		for _, category := range form.Categories {
+			categories = append(categories, types.StringValue(category))
+		}
+		data.Forms[index] = FormsDataSourceFormModel{
+			Name:        types.StringValue(form.Name),
+			Description: types.StringValue(form.Description),
+			Categories:  categories,
+		}
+	}
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Debug(ctx, fmt.Sprintf("read a data source: %#v", data))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
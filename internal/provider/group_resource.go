@@ -0,0 +1,306 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &GroupResource{}
+	_ resource.ResourceWithConfigure   = &GroupResource{}
+	_ resource.ResourceWithImportState = &GroupResource{}
+)
+
+// NewGroupResource is a helper function to simplify the provider implementation.
+func NewGroupResource() resource.Resource {
+	return &GroupResource{
+		config: resourceOrDataSourceConfig{
+			name: "group_resource",
+		},
+	}
+}
+
+// GroupResource is the resource implementation.
+type GroupResource struct {
+	config resourceOrDataSourceConfig
+}
+
+// GroupResourceModel maps the resource schema data.
+type GroupResourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Role          types.String `tfsdk:"role"`
+	Members       types.Set    `tfsdk:"members"`
+}
+
+// Metadata returns the resource type name.
+func (r *GroupResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.config.name
+}
+
+// Schema defines the schema for the resource.
+func (r *GroupResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Group resource manages an access group of Ansible Forms, and optionally its members.",
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Connection profile name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "ID of a group.",
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Name of a group.",
+			},
+			"role": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Role of a group.",
+			},
+			"members": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Usernames of the members of a group. Users added or removed outside of Terraform are reported as drift. " +
+					"When unset, members are not managed, and they are not read on import.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *GroupResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	r.config.providerConfig = config
+}
+
+// request converts the model to the group sent to Ansible Forms.
+func (data *GroupResourceModel) request() interfaces.GroupResourceModel {
+	return interfaces.GroupResourceModel{
+		Name: data.Name.ValueString(),
+		Role: data.Role.ValueString(),
+	}
+}
+
+// setMembers adds and removes members so that the group has the desired members, and returns the members read back from the server.
+// Errors are reported in diags, members added or removed until then are still returned.
+func setMembers(ctx context.Context, diags *diag.Diagnostics, client restclient.RestClient, id string, desired types.Set) types.Set {
+	errorHandler := utils.NewErrorHandler(ctx, diags)
+	var desiredMembers []string
+	diags.Append(desired.ElementsAs(ctx, &desiredMembers, false)...)
+	if diags.HasError() {
+		return desired
+	}
+	current, err := interfaces.GetGroupMembers(errorHandler, client, id)
+	if err != nil {
+		return desired
+	}
+	add, remove := interfaces.GroupMemberChanges(current, desiredMembers)
+	tflog.Debug(ctx, fmt.Sprintf("group %s: adding members %v, removing members %v", id, add, remove))
+	for _, username := range add {
+		if interfaces.AddGroupMember(errorHandler, client, id, username) != nil {
+			break
+		}
+	}
+	for _, username := range remove {
+		if diags.HasError() || interfaces.RemoveGroupMember(errorHandler, client, id, username) != nil {
+			break
+		}
+	}
+
+	var readDiags diag.Diagnostics
+	members, err := interfaces.GetGroupMembers(utils.NewErrorHandler(ctx, &readDiags), client, id)
+	if err != nil {
+		diags.Append(readDiags...)
+		return desired
+	}
+	value, d := types.SetValueFrom(ctx, types.StringType, members)
+	diags.Append(d...)
+
+	return value
+}
+
+// Create a new resource.
+func (r *GroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *GroupResourceModel
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	id, err := interfaces.CreateGroup(errorHandler, *client, data.request())
+	if err != nil {
+		// error reporting done inside CreateGroup
+		return
+	}
+	data.ID = types.StringValue(strconv.FormatInt(id, 10))
+	// the group is saved in state even if members cannot be set, so that it is tracked (and tainted).
+	if !data.Members.IsNull() {
+		data.Members = setMembers(ctx, &resp.Diagnostics, *client, data.ID.ValueString(), data.Members)
+	}
+
+	tflog.Trace(ctx, "created a resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *GroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *GroupResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	group, err := interfaces.GetGroupByID(errorHandler, *client, data.ID.ValueString())
+	if err != nil {
+		return
+	}
+	if group == nil {
+		tflog.Info(ctx, fmt.Sprintf("group %s no longer exists, removing it from state", data.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.Name = types.StringValue(group.Name)
+	data.Role = stringValueOrNull(data.Role, group.Role)
+	if !data.Members.IsNull() {
+		members, err := interfaces.GetGroupMembers(errorHandler, *client, data.ID.ValueString())
+		if err != nil {
+			return
+		}
+		var d diag.Diagnostics
+		data.Members, d = types.SetValueFrom(ctx, types.StringType, members)
+		resp.Diagnostics.Append(d...)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("read a group resource: %s", data.ID.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *GroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var plan, state *GroupResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, plan.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	plan.ID = state.ID
+	if !plan.Name.Equal(state.Name) || !plan.Role.Equal(state.Role) {
+		if err = interfaces.UpdateGroup(errorHandler, *client, state.ID.ValueString(), plan.request()); err != nil {
+			return
+		}
+	}
+	// only the members that changed are added or removed.
+	if !plan.Members.IsNull() && !plan.Members.Equal(state.Members) {
+		plan.Members = setMembers(ctx, &resp.Diagnostics, *client, state.ID.ValueString(), plan.Members)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *GroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *GroupResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+	if err = interfaces.DeleteGroupByID(errorHandler, *client, data.ID.ValueString()); err != nil {
+		return
+	}
+}
+
+// ImportState imports a group, the import ID is <cx_profile_name>,<id>.
+func (r *GroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	cxProfileName, id, found := strings.Cut(req.ID, ",")
+	if !found || cxProfileName == "" || id == "" {
+		resp.Diagnostics.AddError("invalid import ID",
+			fmt.Sprintf("Expected <cx_profile_name>,<id>, got %q.", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cx_profile_name"), cxProfileName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
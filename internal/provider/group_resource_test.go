@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGroupResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccGroupResourceConfig("tf_acc_group", `["admin"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_group_resource.group", "name", "tf_acc_group"),
+					resource.TestCheckResourceAttr("ansible-forms_group_resource.group", "members.#", "1"),
+					resource.TestCheckResourceAttrSet("ansible-forms_group_resource.group", "id")),
+			},
+			{
+				Config: testAccGroupResourceConfig("tf_acc_group", `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_group_resource.group", "members.#", "0")),
+			},
+			{
+				ResourceName: "ansible-forms_group_resource.group",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return "cluster4," + s.RootModule().Resources["ansible-forms_group_resource.group"].Primary.ID, nil
+				},
+				ImportStateVerify: true,
+				// members are not read on import
+				ImportStateVerifyIgnore: []string{"members"},
+			},
+		},
+	})
+}
+
+func testAccGroupResourceConfig(name string, members string) string {
+	// environment variables are checked in testAccPreCheck
+	host := os.Getenv("TF_ACC_ANSIBLE_FORMS_HOST")
+	admin := os.Getenv("TF_ACC_ANSIBLE_FORMS_USER")
+	password := os.Getenv("TF_ACC_ANSIBLE_FORMS_PASS")
+	return fmt.Sprintf(`
+provider "ansible-forms" {
+ connection_profiles = [
+    {
+      name = "cluster4"
+      hostname = "%s"
+      username = "%s"
+      password = "%s"
+      validate_certs = false
+    },
+  ]
+}
+
+resource "ansible-forms_group_resource" "group" {
+  cx_profile_name = "cluster4"
+  name            = "%s"
+  members         = %s
+}`, host, admin, password, name, members)
+}
@@ -0,0 +1,503 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &JobBatchResource{}
+	_ resource.ResourceWithConfigure      = &JobBatchResource{}
+	_ resource.ResourceWithValidateConfig = &JobBatchResource{}
+)
+
+// NewJobBatchResource is a helper function to simplify the provider implementation.
+func NewJobBatchResource() resource.Resource {
+	return &JobBatchResource{
+		config: resourceOrDataSourceConfig{
+			name: "job_batch_resource",
+		},
+	}
+}
+
+// JobBatchResource launches a form once for each target.
+type JobBatchResource struct {
+	config resourceOrDataSourceConfig
+}
+
+// JobBatchResourceModel maps the resource schema data.
+type JobBatchResourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	ID            types.String `tfsdk:"id"`
+	FormName      types.String `tfsdk:"form_name"`
+	Extravars     types.Map    `tfsdk:"extravars"`
+	Targets       types.List   `tfsdk:"targets"`
+	// CompletionTimeout overrides the provider job_completion_timeout for all the jobs.
+	CompletionTimeout types.Int64 `tfsdk:"completion_timeout"`
+	// Status aggregates the statuses of the jobs, Jobs maps each target to its job.
+	Status types.String `tfsdk:"status"`
+	Jobs   types.Map    `tfsdk:"jobs"`
+}
+
+// JobBatchJobModel describes the job launched for a target.
+type JobBatchJobModel struct {
+	ID     types.String `tfsdk:"id"`
+	Status types.String `tfsdk:"status"`
+	JobURL types.String `tfsdk:"job_url"`
+}
+
+// jobBatchJobType is the object type of the jobs map.
+var jobBatchJobType = types.ObjectType{AttrTypes: map[string]attr.Type{
+	"id":      types.StringType,
+	"status":  types.StringType,
+	"job_url": types.StringType,
+}}
+
+// jobBatchResult is the outcome of the job launched for a target, with the diagnostics reported while launching it.
+type jobBatchResult struct {
+	target string
+	job    *interfaces.JobGetDataSourceModel
+	diags  diag.Diagnostics
+}
+
+// Metadata returns the resource type name.
+func (r *JobBatchResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.config.name
+}
+
+// Schema defines the schema for the resource.
+func (r *JobBatchResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Launches a form once for each target, passing the target as the `--limit` of its job, and waits for all the jobs to complete. " +
+			"Jobs are launched in parallel, up to the `max_concurrent_requests` of the connection profile when set. " +
+			"The resource fails when any job fails, the jobs that were launched are kept in the state.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name",
+				Optional:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Comma separated ids of the jobs, in the order of `targets`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"form_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the form launched for each target. Changing it launches new jobs.",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"extravars": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Extra vars sent with each job. Changing them launches new jobs.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"targets": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Hosts or groups to run the form against, one job is launched for each of them with the target as its `limit`. " +
+					"Targets must be unique. Changing them launches new jobs.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"completion_timeout": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Time in seconds to wait for all the jobs to complete, overriding the provider `job_completion_timeout`. " +
+					"Must be greater than 0. Defaults to the provider value.",
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "`failed` when any job failed, `running` while a job is still in progress, otherwise `success`. Refreshed on each read.",
+			},
+			"jobs": schema.MapNestedAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Jobs launched, by target. Statuses are refreshed on each read, a job deleted outside of Terraform is removed.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Job id.",
+						},
+						"status": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Status of the job.",
+						},
+						"job_url": schema.StringAttribute{
+							Computed:            true,
+							MarkdownDescription: "Link to the job in the Ansible Forms web UI.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *JobBatchResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected  Resource Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	r.config.providerConfig = config
+}
+
+// ValidateConfig validates the resource configuration.
+func (r *JobBatchResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var completionTimeout types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("completion_timeout"), &completionTimeout)...)
+	if !completionTimeout.IsNull() && !completionTimeout.IsUnknown() && completionTimeout.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("completion_timeout"), "invalid completion_timeout",
+			fmt.Sprintf("completion_timeout must be greater than 0, got %d.", completionTimeout.ValueInt64()))
+	}
+	var targets types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("targets"), &targets)...)
+	if resp.Diagnostics.HasError() || targets.IsUnknown() {
+		return
+	}
+	seen := make(map[string]bool, len(targets.Elements()))
+	for index, element := range targets.Elements() {
+		target, ok := element.(types.String)
+		if !ok || target.IsUnknown() {
+			continue
+		}
+		if strings.TrimSpace(target.ValueString()) == "" {
+			resp.Diagnostics.AddAttributeError(path.Root("targets").AtListIndex(index), "invalid target", "targets must not be empty.")
+		} else if seen[target.ValueString()] {
+			resp.Diagnostics.AddAttributeError(path.Root("targets").AtListIndex(index), "duplicate target",
+				fmt.Sprintf("target %s is listed more than once, targets must be unique.", target.ValueString()))
+		}
+		seen[target.ValueString()] = true
+	}
+}
+
+// completionTimeout returns how long to wait for the jobs, completion_timeout when set, otherwise the provider job_completion_timeout.
+func (r *JobBatchResource) completionTimeout(data *JobBatchResourceModel) time.Duration {
+	if data.CompletionTimeout.ValueInt64() > 0 {
+		return time.Duration(data.CompletionTimeout.ValueInt64()) * time.Second
+	}
+
+	return time.Duration(r.config.providerConfig.JobCompletionTimeOut) * time.Second
+}
+
+// Create launches a job for each target, and waits for them to complete.
+func (r *JobBatchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *JobBatchResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var targets []string
+	resp.Diagnostics.Append(data.Targets.ElementsAs(ctx, &targets, false)...)
+	request := interfaces.JobResourceModel{
+		Form:      data.FormName.ValueString(),
+		Extravars: expandExtravars(ctx, &resp.Diagnostics, data.Extravars),
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// requests are throttled by the client of each job, the number of jobs in flight is bounded as well.
+	workers := len(targets)
+	if profile, err := r.config.providerConfig.GetConnectionProfile(data.CxProfileName.ValueString()); err == nil && profile.MaxConcurrentRequests > 0 {
+		workers = profile.MaxConcurrentRequests
+	}
+	deadline := time.Now().Add(r.completionTimeout(data))
+	results := runJobBatch(targets, workers, func(target string) jobBatchResult {
+		return r.launchBatchJob(ctx, data, request, target, deadline)
+	})
+
+	jobs := make([]*interfaces.JobGetDataSourceModel, 0, len(results))
+	for _, result := range results {
+		resp.Diagnostics.Append(result.diags...)
+		if result.job != nil && result.job.IsFailed() {
+			resp.Diagnostics.AddError(fmt.Sprintf("Job for target %s completed with status=%s", result.target, result.job.Status),
+				fmt.Sprintf("job %d for form %s failed: %s", result.job.ID, request.Form, result.job.FailureReason()))
+		}
+		jobs = append(jobs, result.job)
+	}
+	// the launched jobs are saved even on error, so that they are tracked, and deleted with the resource.
+	if r.setBatchJobs(ctx, &resp.Diagnostics, data, targets, jobs) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+}
+
+// runJobBatch calls launch for each target, with up to workers calls in parallel, and returns the results in the order of targets.
+func runJobBatch(targets []string, workers int, launch func(target string) jobBatchResult) []jobBatchResult {
+	if workers <= 0 || workers > len(targets) {
+		workers = len(targets)
+	}
+	results := make([]jobBatchResult, len(targets))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				results[index] = launch(targets[index])
+				results[index].target = targets[index]
+			}
+		}()
+	}
+	for index := range targets {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// launchBatchJob launches the job of a target, and waits for it to complete until deadline.
+// It uses its own client and diagnostics, as it runs in parallel with the jobs of other targets.
+func (r *JobBatchResource) launchBatchJob(ctx context.Context, data *JobBatchResourceModel, request interfaces.JobResourceModel, target string, deadline time.Time) jobBatchResult {
+	var result jobBatchResult
+	errorHandler := utils.NewErrorHandler(ctx, &result.diags)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return result
+	}
+	request.Limit = target
+	job, err := interfaces.CreateJob(errorHandler, *client, request)
+	if err != nil {
+		// field errors are not reported by CreateJob
+		var fieldErrors interfaces.JobFieldErrors
+		if errors.As(err, &fieldErrors) {
+			reportJobFieldErrors(&result.diags, []jobFieldInput{{attribute: "extravars", fields: data.Extravars.Elements(), mapKeys: true}}, fieldErrors)
+		}
+		tflog.Debug(ctx, "err creating a job", map[string]interface{}{"target": target, "err": err})
+		return result
+	}
+	result.job = &job.Data
+	tflog.Info(ctx, fmt.Sprintf("launched job %d for target %s", job.Data.ID, target))
+
+	waitOptions := interfaces.JobWaitOptions{
+		Timeout:            time.Until(deadline),
+		PollInterval:       jobPollInterval,
+		MaxPollInterval:    jobMaxPollInterval,
+		StreamOutput:       r.config.providerConfig.StreamJobOutput,
+		MaxTransientErrors: r.config.providerConfig.JobPollMaxTransientErrors,
+		LongPollTimeout:    r.config.providerConfig.longPollTimeout(),
+	}
+	// on error, the job is still saved so it is tracked, error reporting done inside WaitForJob
+	completedJob, _ := interfaces.WaitForJob(errorHandler, *client, strconv.FormatInt(job.Data.ID, 10), waitOptions)
+	if completedJob != nil {
+		completedJob.ID = job.Data.ID
+		result.job = completedJob
+	}
+
+	return result
+}
+
+// setBatchJobs sets id, status, and jobs from the jobs of targets, nil for a target whose job was not launched.
+// It returns false when no job was launched.
+func (r *JobBatchResource) setBatchJobs(ctx context.Context, diags *diag.Diagnostics, data *JobBatchResourceModel, targets []string, jobs []*interfaces.JobGetDataSourceModel) bool {
+	ids := make([]string, 0, len(jobs))
+	statuses := make([]string, 0, len(jobs))
+	jobModels := make(map[string]JobBatchJobModel, len(jobs))
+	for index, job := range jobs {
+		if job == nil {
+			continue
+		}
+		id := strconv.FormatInt(job.ID, 10)
+		ids = append(ids, id)
+		statuses = append(statuses, job.Status)
+		jobModels[targets[index]] = JobBatchJobModel{
+			ID:     types.StringValue(id),
+			Status: types.StringValue(job.Status),
+			JobURL: types.StringValue(r.config.providerConfig.JobURL(data.CxProfileName.ValueString(), id)),
+		}
+	}
+	if len(ids) == 0 {
+		return false
+	}
+	data.ID = types.StringValue(strings.Join(ids, ","))
+	data.Status = types.StringValue(jobBatchStatus(statuses))
+	var mapDiags diag.Diagnostics
+	data.Jobs, mapDiags = types.MapValueFrom(ctx, jobBatchJobType, jobModels)
+	diags.Append(mapDiags...)
+
+	return true
+}
+
+// jobBatchStatus aggregates the statuses of the jobs of a batch: failed when any job failed, running while any job is in progress, otherwise success.
+func jobBatchStatus(statuses []string) string {
+	status := "success"
+	for _, jobStatus := range statuses {
+		if interfaces.IsJobFailed(jobStatus) {
+			return "failed"
+		}
+		if !interfaces.IsJobTerminal(jobStatus) {
+			status = "running"
+		}
+	}
+
+	return status
+}
+
+// batchTargets returns the targets of the jobs in state, in the order of their ids.
+func batchTargets(ctx context.Context, diags *diag.Diagnostics, data *JobBatchResourceModel) ([]string, map[string]JobBatchJobModel) {
+	jobModels := make(map[string]JobBatchJobModel)
+	diags.Append(data.Jobs.ElementsAs(ctx, &jobModels, false)...)
+	var targets []string
+	diags.Append(data.Targets.ElementsAs(ctx, &targets, false)...)
+	ordered := make([]string, 0, len(jobModels))
+	for _, target := range targets {
+		if _, ok := jobModels[target]; ok {
+			ordered = append(ordered, target)
+		}
+	}
+
+	return ordered, jobModels
+}
+
+// Read refreshes the statuses of the jobs, and removes the jobs deleted outside of Terraform.
+func (r *JobBatchResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *JobBatchResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	targets, jobModels := batchTargets(ctx, &resp.Diagnostics, data)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+	jobs := make([]*interfaces.JobGetDataSourceModel, len(targets))
+	for index, target := range targets {
+		job, err := interfaces.FindJobByID(errorHandler, *client, jobModels[target].ID.ValueString())
+		if err != nil {
+			return
+		}
+		if job == nil {
+			tflog.Warn(ctx, fmt.Sprintf("job %s of target %s not found, removing it from state", jobModels[target].ID.ValueString(), target))
+			continue
+		}
+		jobs[index] = job
+	}
+
+	if !r.setBatchJobs(ctx, &resp.Diagnostics, data, targets, jobs) {
+		// all the jobs were deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update only keeps the attributes that do not launch new jobs in sync with the configuration.
+func (r *JobBatchResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state *JobBatchResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.CxProfileName = plan.CxProfileName
+	state.CompletionTimeout = plan.CompletionTimeout
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Delete cancels the jobs that are still running, and deletes the jobs.
+func (r *JobBatchResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *JobBatchResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	targets, jobModels := batchTargets(ctx, &resp.Diagnostics, data)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+	waitOptions := interfaces.JobWaitOptions{
+		Timeout:            r.completionTimeout(data),
+		PollInterval:       jobPollInterval,
+		MaxPollInterval:    jobMaxPollInterval,
+		MaxTransientErrors: r.config.providerConfig.JobPollMaxTransientErrors,
+		LongPollTimeout:    r.config.providerConfig.longPollTimeout(),
+	}
+	for _, target := range targets {
+		id := jobModels[target].ID.ValueString()
+		// error reporting done inside CancelJobByID and DeleteJobByID
+		if err = interfaces.CancelJobByID(errorHandler, *client, id, waitOptions); err != nil {
+			return
+		}
+		if err = interfaces.DeleteJobByID(errorHandler, *client, id); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+)
+
+func TestRunJobBatch(t *testing.T) {
+	targets := []string{"host1", "host2", "host3", "host4", "host5"}
+	var mutex sync.Mutex
+	running, maxRunning := 0, 0
+	results := runJobBatch(targets, 2, func(target string) jobBatchResult {
+		mutex.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mutex.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mutex.Lock()
+		running--
+		mutex.Unlock()
+		return jobBatchResult{job: &interfaces.JobGetDataSourceModel{Target: target}}
+	})
+
+	if maxRunning > 2 {
+		t.Errorf("runJobBatch() ran %d jobs at once, want at most 2", maxRunning)
+	}
+	var got []string
+	for _, result := range results {
+		if result.target != result.job.Target {
+			t.Errorf("runJobBatch() result for %s holds the job of %s", result.target, result.job.Target)
+		}
+		got = append(got, result.target)
+	}
+	if !reflect.DeepEqual(got, targets) {
+		t.Errorf("runJobBatch() targets = %v, want %v", got, targets)
+	}
+}
+
+func TestJobBatchStatus(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		want     string
+	}{
+		{statuses: []string{"success", "warning"}, want: "success"},
+		{statuses: []string{"success", "running"}, want: "running"},
+		{statuses: []string{"running", "failed", "success"}, want: "failed"},
+	}
+	for _, tt := range tests {
+		if got := jobBatchStatus(tt.statuses); got != tt.want {
+			t.Errorf("jobBatchStatus(%v) = %q, want %q", tt.statuses, got, tt.want)
+		}
+	}
+}
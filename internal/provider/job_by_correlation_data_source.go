@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &JobByCorrelationDataSource{}
+
+// JobByCorrelationDataSource finds the latest job launched with an extra var set to a value, e.g. a correlation id.
+type JobByCorrelationDataSource struct {
+	config resourceOrDataSourceConfig
+}
+
+// NewJobByCorrelationDataSource is a helper function to simplify the provider implementation.
+func NewJobByCorrelationDataSource() datasource.DataSource {
+	return &JobByCorrelationDataSource{
+		config: resourceOrDataSourceConfig{
+			name:       "job_by_correlation_data_source",
+			dataSource: true,
+		},
+	}
+}
+
+// JobByCorrelationDataSourceModel maps the data source schema data.
+type JobByCorrelationDataSourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	FormName      types.String `tfsdk:"form_name"`
+	ExtravarName  types.String `tfsdk:"extravar_name"`
+	ExtravarValue types.String `tfsdk:"extravar_value"`
+	ID            types.Int64  `tfsdk:"id"`
+	Status        types.String `tfsdk:"status"`
+	Start         types.String `tfsdk:"start"`
+	MatchCount    types.Int64  `tfsdk:"match_count"`
+}
+
+// Metadata returns the data source type name.
+func (d *JobByCorrelationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.config.name
+}
+
+// Schema defines the schema for the data source.
+func (d *JobByCorrelationDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Job by correlation data source finds the latest job launched with an extra var set to a value, " +
+			"e.g. a correlation id passed in `extravars`, to find the job of an earlier apply whose id was not recorded. " +
+			"An error is reported when no job matches. When several jobs match, the latest one is returned with a warning. " +
+			"The 200 most recent jobs are searched, newest first, set `form_name` to limit the search to a form. " +
+			"A job is read on its own when the list does not include its extra vars, up to the first match only, " +
+			"so older matching jobs are only found when the list includes their extra vars.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name. When unset, the provider default_connection_profile is used, else the only connection profile, " +
+					"or the profile named `default` when several are defined.",
+				Optional: true,
+			},
+			"form_name": schema.StringAttribute{
+				MarkdownDescription: "Only search the jobs of this form. All jobs are searched when unset.",
+				Optional:            true,
+			},
+			"extravar_name": schema.StringAttribute{
+				MarkdownDescription: "Name of the extra var holding the correlation id, e.g. `correlation_id`.",
+				Required:            true,
+			},
+			"extravar_value": schema.StringAttribute{
+				MarkdownDescription: "Value of the extra var to match. Values that are not strings are matched with their JSON encoding, e.g. `42` or `true`.",
+				Required:            true,
+			},
+			"id": schema.Int64Attribute{
+				MarkdownDescription: "ID of the latest matching job.",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Status of the latest matching job, e.g. running, success, or failed.",
+				Computed:            true,
+			},
+			"start": schema.StringAttribute{
+				MarkdownDescription: "Start time of the latest matching job.",
+				Computed:            true,
+			},
+			"match_count": schema.Int64Attribute{
+				MarkdownDescription: "Number of matching jobs found, see above for the jobs searched.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *JobByCorrelationDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	d.config.providerConfig = config
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *JobByCorrelationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := d.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data JobByCorrelationDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, d.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	name, value := data.ExtravarName.ValueString(), data.ExtravarValue.ValueString()
+	jobs, err := interfaces.FindJobsByExtravar(errorHandler, *client, data.FormName.ValueString(), name, value)
+	if err != nil {
+		// error reporting done inside FindJobsByExtravar
+		return
+	}
+	if len(jobs) == 0 {
+		resp.Diagnostics.AddError("No matching job",
+			fmt.Sprintf("no job found with extra var %s set to %q%s.", name, value, formNameSuffix(data.FormName)))
+		return
+	}
+	if len(jobs) > 1 {
+		ids := make([]int64, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.ID
+		}
+		resp.Diagnostics.AddWarning("Several matching jobs",
+			fmt.Sprintf("%d jobs found with extra var %s set to %q%s, using the latest, job %d. Matching jobs, latest first: %v.",
+				len(jobs), name, value, formNameSuffix(data.FormName), jobs[0].ID, ids))
+	}
+
+	data.ID = types.Int64Value(jobs[0].ID)
+	data.Status = types.StringValue(jobs[0].Status)
+	data.Start = types.StringValue(jobs[0].Start)
+	data.MatchCount = types.Int64Value(int64(len(jobs)))
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Debug(ctx, fmt.Sprintf("read a data source: %#v", data))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// formNameSuffix describes the form_name filter in diagnostics, empty when it is not set.
+func formNameSuffix(formName types.String) string {
+	if formName.ValueString() == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" for form %s", formName.ValueString())
+}
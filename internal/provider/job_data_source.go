@@ -25,7 +25,8 @@ type JobDataSource struct {
 func NewJobDataSource() datasource.DataSource {
 	return &JobDataSource{
 		config: resourceOrDataSourceConfig{
-			name: "job_data_source",
+			name:       "job_data_source",
+			dataSource: true,
 		},
 	}
 }
@@ -61,8 +62,9 @@ func (d *JobDataSource) Schema(ctx context.Context, req datasource.SchemaRequest
 
 		Attributes: map[string]schema.Attribute{
 			"cx_profile_name": schema.StringAttribute{
-				MarkdownDescription: "Connection profile name",
-				Required:            true,
+				MarkdownDescription: "Connection profile name. When unset, the provider default_connection_profile is used, else the only connection profile, " +
+					"or the profile named `default` when several are defined.",
+				Optional: true,
 			},
 			"id": schema.Int64Attribute{
 				MarkdownDescription: "",
@@ -143,6 +145,9 @@ func (d *JobDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 
 // Read refreshes the Terraform state with the latest data.
 func (d *JobDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := d.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
 	var data JobDataSourceModel
 
 	// Read Terraform configuration data into the model
@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var (
+	_ datasource.DataSource                   = &JobOutputDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &JobOutputDataSource{}
+)
+
+// JobOutputDataSource defines the data source implementation.
+type JobOutputDataSource struct {
+	config resourceOrDataSourceConfig
+}
+
+// NewJobOutputDataSource is a helper function to simplify the provider implementation.
+func NewJobOutputDataSource() datasource.DataSource {
+	return &JobOutputDataSource{
+		config: resourceOrDataSourceConfig{
+			name:       "job_output_data_source",
+			dataSource: true,
+		},
+	}
+}
+
+// JobOutputDataSourceModel maps the data source schema data.
+type JobOutputDataSourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	JobID         types.Int64  `tfsdk:"job_id"`
+	TailLines     types.Int64  `tfsdk:"tail_lines"`
+	Output        types.String `tfsdk:"output"`
+	Status        types.String `tfsdk:"status"`
+	Complete      types.Bool   `tfsdk:"complete"`
+}
+
+// Metadata returns the data source type name.
+func (d *JobOutputDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.config.name
+}
+
+// Schema defines the schema for the data source.
+func (d *JobOutputDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Job output data source reads the output of any job, including jobs not managed by Terraform, e.g. launched by a schedule.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name. When unset, the provider default_connection_profile is used, else the only connection profile, " +
+					"or the profile named `default` when several are defined.",
+				Optional: true,
+			},
+			"job_id": schema.Int64Attribute{
+				MarkdownDescription: "ID of a job.",
+				Required:            true,
+			},
+			"tail_lines": schema.Int64Attribute{
+				MarkdownDescription: "Only keep the last lines of the output, to limit the size of the state. The whole output is kept when unset.",
+				Optional:            true,
+			},
+			"output": schema.StringAttribute{
+				MarkdownDescription: "Output of a job, so far when the job is not complete.",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Status of a job, e.g. running, success, or failed.",
+				Computed:            true,
+			},
+			"complete": schema.BoolAttribute{
+				MarkdownDescription: "Whether the job reached a final status, so that its output no longer changes.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *JobOutputDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Job Output Data Source Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	d.config.providerConfig = config
+}
+
+// ValidateConfig validates the data source configuration.
+func (d *JobOutputDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var tailLines types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tail_lines"), &tailLines)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !tailLines.IsNull() && !tailLines.IsUnknown() && tailLines.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("tail_lines"), "invalid tail_lines",
+			fmt.Sprintf("tail_lines must be greater than 0, got %d.", tailLines.ValueInt64()))
+	}
+}
+
+// tailLines returns the last n lines of output, or output when it has n lines or less.
+func tailLines(output string, n int) string {
+	trimmed := strings.TrimSuffix(output, "\n")
+	index := len(trimmed)
+	for i := 0; i < n; i++ {
+		index = strings.LastIndex(trimmed[:index], "\n")
+		if index < 0 {
+			return output
+		}
+	}
+
+	return output[index+1:]
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *JobOutputDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := d.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data JobOutputDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	// we need to defer setting the client until we can read the connection profile name
+	client, err := getRestClient(errorHandler, d.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	id := strconv.FormatInt(data.JobID.ValueInt64(), 10)
+	job, err := interfaces.FindJobByID(errorHandler, *client, id)
+	if err != nil {
+		// error reporting done inside FindJobByID
+		return
+	}
+	if job == nil {
+		errorHandler.MakeAndReportError("job not found", fmt.Sprintf("job %s does not exist", id))
+		return
+	}
+
+	output := job.Output
+	if !data.TailLines.IsNull() {
+		output = tailLines(output, int(data.TailLines.ValueInt64()))
+	}
+	data.Output = types.StringValue(output)
+	data.Status = types.StringValue(job.Status)
+	data.Complete = types.BoolValue(interfaces.IsJobTerminal(job.Status))
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Debug(ctx, fmt.Sprintf("read a data source: job %s, status %s, %d bytes of output", id, job.Status, len(output)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
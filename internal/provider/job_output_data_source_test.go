@@ -0,0 +1,25 @@
+package provider
+
+import "testing"
+
+func TestTailLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		n      int
+		want   string
+	}{
+		{name: "empty", output: "", n: 2, want: ""},
+		{name: "fewer_lines", output: "a\nb\n", n: 5, want: "a\nb\n"},
+		{name: "same_lines", output: "a\nb", n: 2, want: "a\nb"},
+		{name: "tail", output: "a\nb\nc\n", n: 2, want: "b\nc\n"},
+		{name: "tail_partial_line", output: "a\nb\nc", n: 1, want: "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tailLines(tt.output, tt.n); got != tt.want {
+				t.Errorf("tailLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,28 +1,59 @@
 package provider
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/exp/maps"
 
 	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/restclient"
 	"terraform-provider-ansible-forms/internal/utils"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &JobResource{}
-	_ resource.ResourceWithConfigure = &JobResource{}
+	_ resource.Resource                   = &JobResource{}
+	_ resource.ResourceWithConfigure      = &JobResource{}
+	_ resource.ResourceWithImportState    = &JobResource{}
+	_ resource.ResourceWithValidateConfig = &JobResource{}
+	_ resource.ResourceWithModifyPlan     = &JobResource{}
+)
+
+// jobPollInterval is the delay between the first two polls while waiting for a job to complete,
+// doubled after each poll up to jobMaxPollInterval so that long jobs are polled less often.
+const (
+	jobPollInterval    = 2 * time.Second
+	jobMaxPollInterval = 30 * time.Second
+	// jobRetryDelay is the wait before relaunching a failed job when retry_delay is not set.
+	jobRetryDelay = 30 * time.Second
+	// jobMinCreateTimeout is the shortest timeouts.create, which leaves time for a few polls.
+	jobMinCreateTimeout = 5 * jobPollInterval
 )
 
 // NewJobResource is a helper function to simplify the provider implementation.
@@ -55,6 +86,59 @@ type JobResourceModel struct {
 	Start         types.String `tfsdk:"start"`
 	End           types.String `tfsdk:"end"`
 	Approval      types.String `tfsdk:"approval"`
+	DedupWindow   types.Int64  `tfsdk:"dedup_window"`
+	// ExtendTimeoutOnProgress and MaxTotalTimeout control how long Create waits for the job.
+	ExtendTimeoutOnProgress types.Bool  `tfsdk:"extend_timeout_on_progress"`
+	MaxTotalTimeout         types.Int64 `tfsdk:"max_total_timeout"`
+	// OutputLines and OutputTruncated are derived from Output, OutputMaxLength limits its size in state.
+	// OutputLines is a types.List rather than a slice, as it is unknown in the plan of a new run.
+	// OutputFile receives the whole output, see writeJobOutputFile.
+	OutputLines     types.List   `tfsdk:"output_lines"`
+	OutputTruncated types.Bool   `tfsdk:"output_truncated"`
+	OutputMaxLength types.Int64  `tfsdk:"output_max_length"`
+	OutputFile      types.String `tfsdk:"output_file"`
+	CheckMode       types.Bool   `tfsdk:"check_mode"`
+	// CompletionTimeout overrides the provider job_completion_timeout for this job.
+	CompletionTimeout types.Int64 `tfsdk:"completion_timeout"`
+	// Limit and Tags restrict the hosts and tasks of the playbook run.
+	Limit types.String `tfsdk:"limit"`
+	Tags  types.List   `tfsdk:"tags"`
+	// RerunOn launches a new run in place when it changes.
+	RerunOn types.String `tfsdk:"rerun_on"`
+	// ValidateInputs checks the required fields of the form before launching a job.
+	ValidateInputs types.Bool `tfsdk:"validate_inputs"`
+	// RetainOnFailure keeps a failed job on the server when the resource is destroyed.
+	RetainOnFailure types.Bool `tfsdk:"retain_on_failure"`
+	// StartedAt, FinishedAt, and DurationSeconds are parsed from Start and End, null when the server omits them.
+	StartedAt       types.String `tfsdk:"started_at"`
+	FinishedAt      types.String `tfsdk:"finished_at"`
+	DurationSeconds types.Int64  `tfsdk:"duration_seconds"`
+	// WaitForCompletion polls the job until it completes, true when null.
+	WaitForCompletion types.Bool `tfsdk:"wait_for_completion"`
+	// ExtravarsFiles maps extra vars to files read when the job is launched, extravars take precedence.
+	ExtravarsFiles types.Map `tfsdk:"extravars_files"`
+	// RetryOnFailure and RetryDelay (in seconds) relaunch a job that failed when it is launched.
+	RetryOnFailure types.Int64 `tfsdk:"retry_on_failure"`
+	RetryDelay     types.Int64 `tfsdk:"retry_delay"`
+	// ExtravarsJSON holds typed extra vars as a JSON object, extravars take precedence.
+	ExtravarsJSON types.String `tfsdk:"extravars_json"`
+	// ReturnOnApprovalWait stops waiting for a job once it waits for an approval, AwaitingApproval reports that state.
+	ReturnOnApprovalWait types.Bool `tfsdk:"return_on_approval_wait"`
+	AwaitingApproval     types.Bool `tfsdk:"awaiting_approval"`
+	// JobURL links to the job in the Ansible Forms web UI.
+	JobURL types.String `tfsdk:"job_url"`
+	// IdempotencyKey is generated when a run is launched, and sent with its launch request.
+	IdempotencyKey types.String `tfsdk:"idempotency_key"`
+	// RawPayload is sent as the launch body as is, for forms the other attributes cannot describe.
+	RawPayload types.String `tfsdk:"raw_payload"`
+	// Playbook and Inventory are the names the job ran with, null when the server does not return them.
+	Playbook  types.String `tfsdk:"playbook"`
+	Inventory types.String `tfsdk:"inventory"`
+	// Timeouts bounds the create, read, and delete operations, see timeoutsBlock.
+	Timeouts types.Object `tfsdk:"timeouts"`
+	// MaxQueueWait (in seconds) bounds the time a launched job may stay queued, QueuePosition reports its position meanwhile.
+	MaxQueueWait  types.Int64 `tfsdk:"max_queue_wait"`
+	QueuePosition types.Int64 `tfsdk:"queue_position"`
 }
 
 // JobResourceModelCredentials ...
@@ -76,22 +160,178 @@ func (r *JobResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 
 		Attributes: map[string]schema.Attribute{
 			"cx_profile_name": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "Connection profile name.",
+				Optional: true,
+				MarkdownDescription: "Connection profile name. When unset, the provider default_connection_profile is used, else the only connection profile, " +
+					"or the profile named `default` when several are defined. Changing it launches a new job, " +
+					"except when setting it after importing a job by id alone.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplaceIf(cxProfileNameRequiresReplace,
+						"Changing the profile launches a new job, except when setting it after an import by id.",
+						"Changing the profile launches a new job, except when setting it after an import by id."),
+				},
 			},
 			"form_name": schema.StringAttribute{
-				Required:            true,
-				MarkdownDescription: "Form name of a job.",
+				Optional:            true,
+				MarkdownDescription: "Form name of a job. Required unless `raw_payload` is set. Changing it launches a new job.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"raw_payload": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Launch request body as a JSON object, usually set with `jsonencode()`, sent as is rather than the body built from " +
+					"`form_name` and the extra vars attributes, for forms these attributes cannot describe. " +
+					"It cannot be set with `form_name`, `extravars`, `extravars_json`, `extravars_files`, `credentials`, `check_mode`, `limit`, or `tags`. " +
+					"`validate_inputs` and `dedup_window` do not apply. Changing it launches a new job.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"extravars": schema.MapAttribute{
-				Required:            true,
+				Optional:            true,
 				ElementType:         types.StringType,
-				MarkdownDescription: "Extra vars of a job.",
+				MarkdownDescription: "Extra vars of a job, sent as form input values when the job is launched. Changing them launches a new job.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"extravars_json": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Extra vars of a job as a JSON object, usually set with `jsonencode()`, for forms expecting booleans, numbers, lists, or objects " +
+					"rather than strings. Values are sent as typed JSON, numbers keep their precision. " +
+					"A value set in `extravars` takes precedence over a value of this object for the same name. Changing it launches a new job.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"extravars_files": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Extra vars of a job read from files, as a map of extra var names to file paths. " +
+					"The files are read when the job is launched, so that large or sensitive values such as private keys are kept out of the configuration. " +
+					"Their contents are masked in logs, and are not saved in the state. A value set in `extravars` takes precedence over a file for the same name. " +
+					"Changing the map launches a new job, changing the contents of a file does not.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
 			},
 			"credentials": schema.MapAttribute{
-				Required:            true,
+				Optional:    true,
+				ElementType: types.StringType,
+				MarkdownDescription: "Credentials of a job, as a map of credential fields of the form to names of credentials defined in Ansible Forms, " +
+					"e.g. `ontap_cred = \"cluster1_admin\"`, so that the playbook runs with the chosen credentials. " +
+					"With `validate_inputs`, the named credentials must exist. Not set with `raw_payload`, which carries its own credentials. Changing them launches a new job.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"dedup_window": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Time in seconds to look back for an identical job (same form and extra vars) before submitting. " +
+					"When one is found that did not fail, it is adopted instead of launching a duplicate. " +
+					"Up to 20 recent jobs are read one by one when the job list does not include their extra vars. " +
+					"It applies when the resource is created or replaced, so a replacement within the window adopts the earlier job. " +
+					"A new run launched by changing `rerun_on` is never deduplicated, use it to run the same job again within the window. " +
+					"Disabled when unset or 0.",
+			},
+			"extend_timeout_on_progress": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Whether to restart the completion timeout each time the job reports progress. " +
+					"With a fixed timeout, a slow job is failed once `job_completion_timeout` elapses even if it is still making progress. " +
+					"With this option, only a job that makes no progress for `job_completion_timeout` seconds times out. Defaults to false.",
+			},
+			"completion_timeout": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Time in seconds to wait for the job to complete, overriding the provider `job_completion_timeout` for this job. " +
+					"Must be greater than 0. Defaults to `timeouts.create` when set, else to the provider value. " +
+					"The provider `operation_timeout` or `timeouts.create`, when reached first, stops the wait earlier.",
+			},
+			"return_on_approval_wait": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Whether to stop waiting for the job as soon as it waits for an approval, leaving `status` to the approval status and " +
+					"`awaiting_approval` to true. The job is refreshed on later reads once it is approved. " +
+					"Defaults to false, waiting through the approval up to the completion timeout.",
+			},
+			"retry_on_failure": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Number of times to relaunch the job when it completes with a failed status, for playbooks that fail intermittently. " +
+					"Each attempt launches a new job, the attributes of the resource reflect the last attempt. " +
+					"Retries stop once the completion timeout of the first attempt is reached. Only used when `wait_for_completion` is true. Defaults to 0.",
+			},
+			"retry_delay": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Time in seconds to wait before relaunching a failed job, see `retry_on_failure`. Defaults to 30.",
+			},
+			"max_total_timeout": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: "Hard cap in seconds on the time spent waiting for the job when `extend_timeout_on_progress` is set. Defaults to 3600 seconds.",
+			},
+			"max_queue_wait": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Time in seconds a launched job may stay queued, e.g. while Ansible Forms runs its maximum number of concurrent jobs, " +
+					"before the apply fails, telling a job that could not start from a job that ran too long. " +
+					"When set, the completion timeout only counts from when the job leaves the queue. " +
+					"The job is kept in state, and canceled when the resource is destroyed. Only used when `wait_for_completion` is true. " +
+					"By default, the time spent queued counts toward the completion timeout.",
+			},
+			"output_max_length": schema.Int64Attribute{
+				Optional: true,
+				MarkdownDescription: "Maximum size in bytes of the job output kept in state, larger outputs are truncated and `output_truncated` is set. " +
+					"Defaults to 65536 bytes, 0 keeps the whole output.",
+			},
+			"output_file": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Path of a local file the whole output of the job is written to once it completes, e.g. to keep the output of a playbook " +
+					"that prints tens of megabytes. The output is streamed to the file as it is received rather than read in memory, " +
+					"and `output` still only keeps up to `output_max_length` bytes of it. The file is written when a job is launched, " +
+					"not on refresh, nor when `wait_for_completion` is false.",
+			},
+			"check_mode": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Whether to run the playbook in check mode (`--check`), reporting changes without making them. Changing it launches a new job. " +
+					"Not all forms support check mode, the server may reject the job, which is reported as an error. " +
+					"`dedup_window` is ignored in check mode, and does not tell check mode jobs apart from regular ones. Defaults to false.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"limit": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Host pattern passed to the playbook as `--limit`. Changing it launches a new job.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"tags": schema.ListAttribute{
+				Optional:            true,
 				ElementType:         types.StringType,
-				MarkdownDescription: "Credentials of a job.",
+				MarkdownDescription: "Tags passed to the playbook as `--tags`. Changing them launches a new job.",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"validate_inputs": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Whether to read the form definition when planning and before launching a job, and fail when the form does not exist, " +
+					"when a required field without a default value is missing from `extravars`, or when a value is not one of the values allowed by its field. " +
+					"Defaults to false, leaving validation to Ansible Forms.",
+			},
+			"retain_on_failure": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Whether to keep the job on the server when the resource is destroyed or replaced and the job has failed, " +
+					"so that it can be inspected in the Ansible Forms UI. Only a job that already failed is kept: " +
+					"a job still running is aborted and deleted as usual, even though aborting it marks it as failed. Defaults to false.",
+			},
+			"wait_for_completion": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Whether to wait for the job to complete. When false, the job is launched and its launch-time `status` is recorded, " +
+					"without waiting: `output` is empty and `finished_at` null until a later `terraform refresh` reads the job again, " +
+					"and a failure of the job is not reported. Defaults to true.",
+			},
+			"rerun_on": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Any value, e.g. a timestamp or a hash. Changing it launches a new run of the job, without replacing the resource. " +
+					"`id` and the other computed attributes then reflect the latest run, earlier runs are kept on the server.",
 			},
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -126,7 +366,22 @@ func (r *JobResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
-				MarkdownDescription: "Output of a job.",
+				MarkdownDescription: "Output of a job, retrieved once the job is no longer running.",
+			},
+			"output_lines": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Output of a job, split into lines.",
+			},
+			"output_truncated": schema.BoolAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Whether the output was truncated to `output_max_length`.",
 			},
 			"counter": schema.Int64Attribute{
 				Computed: true,
@@ -156,6 +411,27 @@ func (r *JobResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 				MarkdownDescription: "End time of a job.",
 			},
+			"started_at": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Start time of a job, in RFC3339 format. Null when Ansible Forms does not return it.",
+			},
+			"finished_at": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "End time of a completed job, in RFC3339 format. Null while the job is running, or when Ansible Forms does not return it.",
+			},
+			"duration_seconds": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Time in seconds between `started_at` and `finished_at`. Null when either is null.",
+			},
 			"approval": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -163,6 +439,55 @@ func (r *JobResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *
 				},
 				MarkdownDescription: "Approval of a job.",
 			},
+			"awaiting_approval": schema.BoolAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Whether the job waits for an approval before its playbook runs, refreshed on each read.",
+			},
+			"job_url": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Link to the job in the Ansible Forms web UI, built from the connection profile hostname and the provider `job_url_template`.",
+			},
+			"idempotency_key": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Random key generated when a job is launched, and sent as the `Idempotency-Key` header of the launch request. " +
+					"A launch request resent after a connection error, which may have reached the server, is then deduplicated by servers that support the header, " +
+					"rather than launching the job twice. Servers without support ignore it. Each attempt of `retry_on_failure` uses its own key, " +
+					"and a new key is generated when `rerun_on` changes.",
+			},
+			"playbook": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Name of the playbook run by the job, refreshed on each read. Null when Ansible Forms does not return it.",
+			},
+			"inventory": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Name of the inventory used by the job, refreshed on each read. Null when Ansible Forms does not return it.",
+			},
+			"queue_position": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "Position of the job in the Ansible Forms queue while it is queued, refreshed on each read. " +
+					"Null once the job left the queue, or when Ansible Forms does not return it.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"timeouts": timeoutsBlock("create", "read", "delete"),
 		},
 	}
 }
@@ -183,10 +508,112 @@ func (r *JobResource) Configure(_ context.Context, req resource.ConfigureRequest
 	r.config.providerConfig = config
 }
 
+// ValidateConfig validates the resource configuration.
+func (r *JobResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var completionTimeout types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("completion_timeout"), &completionTimeout)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !completionTimeout.IsNull() && !completionTimeout.IsUnknown() && completionTimeout.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("completion_timeout"), "invalid completion_timeout",
+			fmt.Sprintf("completion_timeout must be greater than 0, got %d.", completionTimeout.ValueInt64()))
+	}
+	var maxQueueWait types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("max_queue_wait"), &maxQueueWait)...)
+	if !maxQueueWait.IsNull() && !maxQueueWait.IsUnknown() && maxQueueWait.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("max_queue_wait"), "invalid max_queue_wait",
+			fmt.Sprintf("max_queue_wait must be greater than 0, got %d.", maxQueueWait.ValueInt64()))
+	}
+	var extravarsJSON types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("extravars_json"), &extravarsJSON)...)
+	expandExtravarsJSON(&resp.Diagnostics, extravarsJSON)
+	validateRawPayload(ctx, req, resp)
+	var timeouts types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("timeouts"), &timeouts)...)
+	// the job is polled at least a few times before the create timeout is reached
+	validateTimeouts(&resp.Diagnostics, timeouts, map[string]time.Duration{"create": jobMinCreateTimeout})
+	var outputFile types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("output_file"), &outputFile)...)
+	if !outputFile.IsNull() && !outputFile.IsUnknown() && outputFile.ValueString() == "" {
+		resp.Diagnostics.AddAttributeError(path.Root("output_file"), "invalid output_file", "output_file must not be empty.")
+	}
+	for _, name := range []string{"retry_on_failure", "retry_delay"} {
+		var value types.Int64
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &value)...)
+		if !value.IsNull() && !value.IsUnknown() && value.ValueInt64() < 0 {
+			resp.Diagnostics.AddAttributeError(path.Root(name), "invalid "+name,
+				fmt.Sprintf("%s must not be negative, got %d.", name, value.ValueInt64()))
+		}
+	}
+}
+
+// validateRawPayload checks that raw_payload is a JSON object, and that it is not set with the attributes that build the launch body.
+// form_name is required when raw_payload is not set.
+func validateRawPayload(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config JobResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.RawPayload.IsNull() {
+		if config.FormName.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("form_name"), "Missing form_name", "form_name is required unless raw_payload is set.")
+		}
+		return
+	}
+	expandJSONObject(&resp.Diagnostics, "raw_payload", config.RawPayload)
+	conflicts := []struct {
+		name  string
+		value attr.Value
+	}{
+		{name: "form_name", value: config.FormName},
+		{name: "extravars", value: config.Extravars},
+		{name: "extravars_json", value: config.ExtravarsJSON},
+		{name: "extravars_files", value: config.ExtravarsFiles},
+		{name: "credentials", value: config.Credentials},
+		{name: "check_mode", value: config.CheckMode},
+		{name: "limit", value: config.Limit},
+		{name: "tags", value: config.Tags},
+	}
+	for _, conflict := range conflicts {
+		if !conflict.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root(conflict.name), "Conflicting configuration",
+				fmt.Sprintf("%s cannot be set with raw_payload, which is sent as the launch body as is.", conflict.name))
+		}
+	}
+}
+
+// completionTimeout returns how long to wait for the job, completion_timeout when set, then timeouts.create,
+// otherwise the provider job_completion_timeout.
+func (r *JobResource) completionTimeout(data *JobResourceModel) time.Duration {
+	if data.CompletionTimeout.ValueInt64() > 0 {
+		return time.Duration(data.CompletionTimeout.ValueInt64()) * time.Second
+	}
+	// an invalid timeout is reported by ValidateConfig
+	if timeout, _ := timeoutValue(data.Timeouts, "create"); timeout > 0 {
+		return timeout
+	}
+
+	return time.Duration(r.config.providerConfig.JobCompletionTimeOut) * time.Second
+}
+
+// timeout returns the timeout of operation in the timeouts block, 0 when it is not set.
+func (r *JobResource) timeout(diags *diag.Diagnostics, data *JobResourceModel, operation string) time.Duration {
+	timeout, err := timeoutValue(data.Timeouts, operation)
+	if err != nil {
+		diags.AddAttributeError(path.Root("timeouts").AtName(operation), "invalid timeouts."+operation, err.Error())
+	}
+
+	return timeout
+}
+
 // Create a new resource.
 func (r *JobResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
 	var data *JobResourceModel
-	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -194,42 +621,478 @@ func (r *JobResource) Create(ctx context.Context, req resource.CreateRequest, re
 		tflog.Debug(ctx, "error getting req plan")
 		return
 	}
+	ctx, timeoutDone := withTimeout(ctx, &resp.Diagnostics, "create", r.timeout(&resp.Diagnostics, data, "create"))
+	defer timeoutDone()
+
+	if !r.launchJob(ctx, &resp.Diagnostics, data, true) {
+		return
+	}
+
+	tflog.Trace(ctx, "created a resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// launchJob launches a job, or adopts a recent identical job when dedup is set and dedup_window allows it,
+// and waits for it to complete unless wait_for_completion is false.
+// The job attributes of data are set when a job was launched or adopted, in which case the state is to be saved, even on error.
+func (r *JobResource) launchJob(ctx context.Context, diags *diag.Diagnostics, data *JobResourceModel, dedup bool) bool {
+	fileExtravars := readExtravarsFiles(ctx, diags, data.ExtravarsFiles)
+	for _, value := range fileExtravars {
+		if value != "" {
+			ctx = tflog.MaskLogStrings(ctx, value.(string))
+		}
+	}
+	errorHandler := utils.NewErrorHandler(ctx, diags)
 
 	var request interfaces.JobResourceModel
 	request.Form = data.FormName.ValueString()
-	//request.Extravars = data.Extravars.Elements()
+	request.Extravars = mergeExtravars(fileExtravars, mergeExtravars(expandExtravarsJSON(diags, data.ExtravarsJSON), expandExtravars(ctx, diags, data.Extravars)))
+	// credentials map credential fields to credential names, they are sent in the credentials field of the launch body, apart from extravars
+	request.Credentials = expandExtravars(ctx, diags, data.Credentials)
+	request.CheckMode = data.CheckMode.ValueBool()
+	request.Limit = data.Limit.ValueString()
+	var tags []string
+	diags.Append(data.Tags.ElementsAs(ctx, &tags, false)...)
+	request.Tags = strings.Join(tags, ",")
+	request.RawPayload = expandJSONObject(diags, "raw_payload", data.RawPayload)
+	if request.RawPayload != nil {
+		// the form name is only used to describe the job in diagnostics
+		request.Form, _ = request.RawPayload["formName"].(string)
+	}
+	if diags.HasError() {
+		return false
+	}
 
 	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
 	if err != nil {
 		// error reporting done inside NewClient
-		return
+		return false
 	}
 
-	job, err := interfaces.CreateJob(errorHandler, *client, request)
-	if err != nil {
-		tflog.Debug(ctx, "err creating a resource", map[string]interface{}{"err": err})
-		return
+	if data.ValidateInputs.ValueBool() && request.RawPayload == nil && !validateJobInputs(errorHandler, diags, *client, request) {
+		return false
+	}
+
+	var job *interfaces.GetJobResponse
+	// job records do not tell check mode runs apart, a check mode job is always launched.
+	if dedup && data.DedupWindow.ValueInt64() > 0 && !request.CheckMode && request.RawPayload == nil {
+		existing, err := interfaces.FindRecentDuplicateJob(errorHandler, *client, request, time.Duration(data.DedupWindow.ValueInt64())*time.Second)
+		if err != nil {
+			return false
+		}
+		if existing != nil {
+			tflog.Info(ctx, fmt.Sprintf("adopting job %d submitted within dedup_window", existing.ID))
+			diags.AddWarning("Adopted existing job",
+				fmt.Sprintf("job %d for form %s was submitted within the last %d seconds with the same extra vars, no new job was launched", existing.ID, request.Form, data.DedupWindow.ValueInt64()))
+			job = &interfaces.GetJobResponse{Data: *existing}
+		}
+	}
+
+	// the key of a new run is unknown when planned, it is generated once for all attempts of the launch.
+	if data.IdempotencyKey.IsNull() || data.IdempotencyKey.IsUnknown() {
+		data.IdempotencyKey = newIdempotencyKey(diags)
+	}
+	// retries share the completion timeout of the first attempt.
+	deadline := time.Now().Add(r.completionTimeout(data))
+	for attempt := int64(1); ; attempt++ {
+		if job == nil {
+			// a retry is a new launch, which must not be deduplicated with the failed attempt.
+			request.IdempotencyKey = data.IdempotencyKey.ValueString()
+			if attempt > 1 {
+				request.IdempotencyKey = fmt.Sprintf("%s-%d", request.IdempotencyKey, attempt)
+			}
+			job, err = interfaces.CreateJob(errorHandler, *client, request)
+			if err != nil {
+				var fieldErrors interfaces.JobFieldErrors
+				if errors.As(err, &fieldErrors) {
+					reportJobFieldErrors(diags, jobFieldInputs(data), fieldErrors)
+				}
+				tflog.Debug(ctx, "err creating a resource", map[string]interface{}{"err": err})
+				return false
+			}
+		}
+
+		if !data.WaitForCompletion.IsNull() && !data.WaitForCompletion.ValueBool() {
+			if launched, err := interfaces.GetJobByID(errorHandler, *client, strconv.FormatInt(job.Data.ID, 10)); err == nil {
+				// the status known at launch, on error the state is still saved so the job is tracked, error reporting done inside GetJobByID
+				launched.ID = job.Data.ID
+				job.Data = *launched
+			}
+			break
+		}
+		if !r.waitForJob(errorHandler, *client, data, job, time.Until(deadline)) {
+			break
+		}
+		if !r.retryFailedJob(ctx, data, job, attempt, deadline) {
+			diags.AddError(fmt.Sprintf("Job completed with status=%s", job.Data.Status),
+				fmt.Sprintf("job %d for form %s failed: %s", job.Data.ID, request.Form, job.Data.FailureReason()))
+			break
+		}
+		job = nil
 	}
 
 	data.ID = types.StringValue(strconv.FormatInt(job.Data.ID, 10))
+	data.JobURL = types.StringValue(r.config.providerConfig.JobURL(data.CxProfileName.ValueString(), data.ID.ValueString()))
 	data.Status = types.StringValue(job.Data.Status)
 	data.LastUpdated = types.StringValue(time.Now().UTC().Format(time.RFC3339))
 	data.Target = types.StringValue(job.Data.Target)
-	data.Output = types.StringValue(job.Data.Output)
+	if !writeJobOutputFile(diags, errorHandler, *client, data, job.Data) {
+		setJobOutput(diags, data, job.Data)
+	}
 	data.Counter = types.Int64Value(job.Data.Counter)
 	data.NoOfRecords = types.Int64Value(job.Data.NoOfRecords)
 	data.Start = types.StringValue(job.Data.Start)
 	data.End = types.StringValue(job.Data.End)
+	setJobTiming(data, job.Data)
 	data.Approval = types.StringValue(job.Data.Approval)
+	data.AwaitingApproval = types.BoolValue(job.Data.IsAwaitingApproval())
+	data.QueuePosition = jobQueuePosition(job.Data)
+	setJobPlaybook(data, job.Data)
 
 	tflog.Debug(ctx, "JOB ID", map[string]interface{}{"ID": job.Data.ID, "DATA": data})
 
-	tflog.Trace(ctx, "created a resource")
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	return true
+}
+
+// waitForJob waits up to timeout for a launched job to complete, and updates job with its final attributes.
+// It returns whether the job completed with a failed status, which is left to the caller to report.
+func (r *JobResource) waitForJob(errorHandler *utils.ErrorHandler, client restclient.RestClient, data *JobResourceModel, job *interfaces.GetJobResponse, timeout time.Duration) bool {
+	maxTotalTimeout := data.MaxTotalTimeout.ValueInt64()
+	if data.MaxTotalTimeout.IsNull() {
+		maxTotalTimeout = 3600
+	}
+	waitOptions := interfaces.JobWaitOptions{
+		Timeout:            timeout,
+		PollInterval:       jobPollInterval,
+		MaxPollInterval:    jobMaxPollInterval,
+		ExtendOnProgress:   data.ExtendTimeoutOnProgress.ValueBool(),
+		MaxTotalTimeout:    time.Duration(maxTotalTimeout) * time.Second,
+		MaxQueueWait:       time.Duration(data.MaxQueueWait.ValueInt64()) * time.Second,
+		StreamOutput:       r.config.providerConfig.StreamJobOutput,
+		ReturnOnApproval:   data.ReturnOnApprovalWait.ValueBool(),
+		MaxTransientErrors: r.config.providerConfig.JobPollMaxTransientErrors,
+		LongPollTimeout:    r.config.providerConfig.longPollTimeout(),
+	}
+	// on error, the state is still saved so the job is tracked (and tainted), error reporting done inside WaitForJob
+	completedJob, _ := interfaces.WaitForJob(errorHandler, client, strconv.FormatInt(job.Data.ID, 10), waitOptions)
+	if completedJob == nil {
+		return false
+	}
+	completedJob.ID = job.Data.ID
+	job.Data = *completedJob
+
+	// the job ran and failed, REST errors are reported by the client
+	return completedJob.IsFailed()
+}
+
+// retryFailedJob waits for retry_delay before a failed job is relaunched, and returns whether it is to be relaunched.
+// It returns false when retry_on_failure is exhausted, when the delay would exceed the deadline, or when ctx is canceled.
+func (r *JobResource) retryFailedJob(ctx context.Context, data *JobResourceModel, job *interfaces.GetJobResponse, attempt int64, deadline time.Time) bool {
+	retries := data.RetryOnFailure.ValueInt64()
+	if attempt > retries {
+		return false
+	}
+	delay := time.Duration(data.RetryDelay.ValueInt64()) * time.Second
+	if data.RetryDelay.IsNull() {
+		delay = jobRetryDelay
+	}
+	if time.Now().Add(delay).After(deadline) {
+		tflog.Warn(ctx, fmt.Sprintf("job %d failed, not retrying as the completion timeout would be exceeded", job.Data.ID))
+		return false
+	}
+	tflog.Info(ctx, fmt.Sprintf("job %d failed with status %s, launching retry %d of %d in %s", job.Data.ID, job.Data.Status, attempt, retries, delay))
+	select {
+	case <-ctx.Done():
+		tflog.Warn(ctx, fmt.Sprintf("job %d failed, not retrying: %s", job.Data.ID, ctx.Err()))
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
+// setJobOutput stores the job output in the model once the job is no longer running, truncated to output_max_length.
+func setJobOutput(diags *diag.Diagnostics, data *JobResourceModel, job interfaces.JobGetDataSourceModel) {
+	if job.IsRunning() {
+		data.Output = types.StringValue("")
+		data.OutputLines = types.ListNull(types.StringType)
+		data.OutputTruncated = types.BoolValue(false)
+		return
+	}
+	output, truncated := interfaces.TruncateJobOutput(job.Output, int(outputMaxLength(data)))
+	setJobOutputValue(diags, data, job.ID, output, truncated, int64(len(job.Output)))
+}
+
+// outputMaxLength returns output_max_length, 65536 when it is not set.
+func outputMaxLength(data *JobResourceModel) int64 {
+	if data.OutputMaxLength.IsNull() {
+		return 65536
+	}
+
+	return data.OutputMaxLength.ValueInt64()
+}
+
+// setJobOutputValue stores output, the start of the size bytes of the output of job id when truncated, in the model.
+// The truncation is reported once, when the job output is first stored: not on each Read of an output already truncated in state.
+func setJobOutputValue(diags *diag.Diagnostics, data *JobResourceModel, id int64, output string, truncated bool, size int64) {
+	if truncated && !data.OutputTruncated.ValueBool() {
+		diags.AddWarning("Job output truncated",
+			fmt.Sprintf("output of job %d is %d bytes, only the first %d bytes are kept in state, see output_max_length", id, size, outputMaxLength(data)))
+	}
+	data.Output = types.StringValue(output)
+	lines := []attr.Value{}
+	for _, line := range flattenTypesStringList(interfaces.JobOutputLines(output)) {
+		lines = append(lines, line)
+	}
+	data.OutputLines = types.ListValueMust(types.StringType, lines)
+	data.OutputTruncated = types.BoolValue(truncated)
+}
+
+// writeJobOutputFile streams the output of a completed job to output_file, and stores up to output_max_length bytes of it in the model,
+// so that a large output is not held in memory once more.  The file is written to a temporary file renamed once complete.
+// It returns false when output_file is not set or the job is still running, or when the output could not be written,
+// in which case the output of the job record is to be stored, error reporting done inside writeJobOutputFile.
+func writeJobOutputFile(diags *diag.Diagnostics, errorHandler *utils.ErrorHandler, client restclient.RestClient, data *JobResourceModel, job interfaces.JobGetDataSourceModel) bool {
+	if data.OutputFile.IsNull() || data.OutputFile.IsUnknown() || job.IsRunning() {
+		return false
+	}
+	name := data.OutputFile.ValueString()
+	file, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		_ = errorHandler.MakeAndReportError("error writing job output file", fmt.Sprintf("cannot create file %s for the output of job %d: %s", name, job.ID, err))
+		return false
+	}
+	defer os.Remove(file.Name())
+	buffer := &interfaces.JobOutputBuffer{MaxLength: int(outputMaxLength(data))}
+	_, err = interfaces.StreamJobOutput(errorHandler, client, strconv.FormatInt(job.ID, 10), io.MultiWriter(file, buffer))
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = errorHandler.MakeAndReportError("error writing job output file", fmt.Sprintf("cannot write file %s for the output of job %d: %s", name, job.ID, closeErr))
+	}
+	if err != nil {
+		// error reporting done inside StreamJobOutput, or above
+		return false
+	}
+	if err := os.Rename(file.Name(), name); err != nil {
+		_ = errorHandler.MakeAndReportError("error writing job output file", fmt.Sprintf("cannot write file %s for the output of job %d: %s", name, job.ID, err))
+		return false
+	}
+	output, truncated := buffer.Output()
+	setJobOutputValue(diags, data, job.ID, output, truncated, buffer.Size())
+
+	return true
+}
+
+// setJobTiming sets started_at, finished_at, and duration_seconds from the start and end of a job, null when they are not available.
+func setJobTiming(data *JobResourceModel, job interfaces.JobGetDataSourceModel) {
+	data.StartedAt = types.StringNull()
+	data.FinishedAt = types.StringNull()
+	data.DurationSeconds = types.Int64Null()
+	startedAt, started := job.StartedAt()
+	if started {
+		data.StartedAt = types.StringValue(startedAt.UTC().Format(time.RFC3339))
+	}
+	finishedAt, finished := job.FinishedAt()
+	if finished {
+		data.FinishedAt = types.StringValue(finishedAt.UTC().Format(time.RFC3339))
+	}
+	if started && finished {
+		data.DurationSeconds = types.Int64Value(int64(finishedAt.Sub(startedAt).Round(time.Second).Seconds()))
+	}
+}
+
+// setJobPlaybook sets the playbook and inventory of a job, null when the server does not return them.
+func setJobPlaybook(data *JobResourceModel, job interfaces.JobGetDataSourceModel) {
+	data.Playbook = types.StringNull()
+	data.Inventory = types.StringNull()
+	if job.Playbook != "" {
+		data.Playbook = types.StringValue(job.Playbook)
+	}
+	if job.Inventory != "" {
+		data.Inventory = types.StringValue(job.Inventory)
+	}
+}
+
+// jobQueuePosition returns the queue position of a queued job, null when the job is not queued or the server does not return it.
+func jobQueuePosition(job interfaces.JobGetDataSourceModel) types.Int64 {
+	if !job.IsQueued() {
+		return types.Int64Null()
+	}
+
+	return types.Int64PointerValue(job.QueuePosition)
+}
+
+// validateJobInputs reports whether the form of a job exists, whether all its required inputs are set,
+// and whether each input is one of the values allowed by its field.
+// Invalid inputs are reported on extravars, so that they fail before a job is launched.
+func validateJobInputs(errorHandler *utils.ErrorHandler, diags *diag.Diagnostics, client restclient.RestClient, request interfaces.JobResourceModel) bool {
+	form, err := interfaces.GetFormByName(errorHandler, client, request.Form)
+	if err != nil {
+		// error reporting done inside GetFormByName
+		return false
+	}
+	valid := true
+	if missing := form.MissingInputs(request.Extravars); len(missing) != 0 {
+		diags.AddAttributeError(path.Root("extravars"), "Missing required form inputs",
+			fmt.Sprintf("form %s requires %s, set them in extravars.", request.Form, strings.Join(missing, ", ")))
+		valid = false
+	}
+	for _, fieldError := range form.InvalidInputs(request.Extravars) {
+		diags.AddAttributeError(path.Root("extravars").AtMapKey(fieldError.Field), "Invalid form input", fieldError.Message)
+		valid = false
+	}
+	if len(request.Credentials) == 0 {
+		return valid
+	}
+	credentials, err := interfaces.GetCredentials(errorHandler, client)
+	if err != nil {
+		// error reporting done inside GetCredentials
+		return false
+	}
+	fields := maps.Keys(request.Credentials)
+	sort.Strings(fields)
+	for _, field := range fields {
+		name, _ := request.Credentials[field].(string)
+		if !interfaces.HasCredential(credentials, name) {
+			diags.AddAttributeError(path.Root("credentials").AtMapKey(field), "Unknown credential",
+				fmt.Sprintf("credential %s does not exist in Ansible Forms.", name))
+			valid = false
+		}
+	}
+
+	return valid
+}
+
+// expandExtravars converts the extravars attribute for the launch payload, nil when null or empty.
+func expandExtravars(ctx context.Context, diags *diag.Diagnostics, extravars types.Map) map[string]any {
+	if extravars.IsNull() || extravars.IsUnknown() || len(extravars.Elements()) == 0 {
+		return nil
+	}
+	var values map[string]string
+	diags.Append(extravars.ElementsAs(ctx, &values, false)...)
+	result := make(map[string]any, len(values))
+	for key, value := range values {
+		result[key] = value
+	}
+
+	return result
+}
+
+// expandExtravarsJSON decodes the extravars_json attribute for the launch payload, nil when null or unknown.
+// Numbers are kept as json.Number, so that they are sent as written.  A value that is not a JSON object is reported on the attribute.
+func expandExtravarsJSON(diags *diag.Diagnostics, extravarsJSON types.String) map[string]any {
+	return expandJSONObject(diags, "extravars_json", extravarsJSON)
+}
+
+// expandJSONObject decodes the JSON object of the attribute name, keeping the precision of numbers, nil when null or unknown.
+func expandJSONObject(diags *diag.Diagnostics, name string, value types.String) map[string]any {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value.ValueString())))
+	decoder.UseNumber()
+	var result map[string]any
+	if err := decoder.Decode(&result); err != nil || decoder.More() {
+		if err == nil {
+			err = errors.New("unexpected data after the object")
+		}
+		diags.AddAttributeError(path.Root(name), "Invalid "+name,
+			fmt.Sprintf("%s must be a JSON object, such as jsonencode({ enabled = true }): %s", name, err))
+		return nil
+	}
+	if result == nil {
+		diags.AddAttributeError(path.Root(name), "Invalid "+name, name+" must be a JSON object, got null.")
+	}
+
+	return result
+}
+
+// readExtravarsFiles reads the files of the extravars_files attribute, nil when null or empty.
+// A file that cannot be read is reported on its key.
+func readExtravarsFiles(ctx context.Context, diags *diag.Diagnostics, extravarsFiles types.Map) map[string]any {
+	if extravarsFiles.IsNull() || extravarsFiles.IsUnknown() || len(extravarsFiles.Elements()) == 0 {
+		return nil
+	}
+	var paths map[string]string
+	diags.Append(extravarsFiles.ElementsAs(ctx, &paths, false)...)
+	result := make(map[string]any, len(paths))
+	for key, name := range paths {
+		content, err := os.ReadFile(name)
+		if err != nil {
+			diags.AddAttributeError(path.Root("extravars_files").AtMapKey(key), "Failed to read extra var file",
+				fmt.Sprintf("cannot read file %s for extra var %s: %s", name, key, err))
+			continue
+		}
+		result[key] = string(content)
+	}
+
+	return result
+}
+
+// mergeExtravars returns the base values overridden by the extravars values, nil when both are nil.  base is modified.
+func mergeExtravars(base, extravars map[string]any) map[string]any {
+	if base == nil {
+		return extravars
+	}
+	for key, value := range extravars {
+		base[key] = value
+	}
+
+	return base
+}
+
+// jobFieldInput is an attribute whose keys are sent as form fields.
+// Fields of extravars_json are keys of a JSON string rather than of a map, they are reported on the attribute itself.
+type jobFieldInput struct {
+	attribute string
+	fields    map[string]attr.Value
+	mapKeys   bool
+}
+
+// jobFieldInputs returns the attributes of a job that set form fields, in the order in which a field is looked up when it is rejected:
+// extravars take precedence over extravars_json and extravars_files, credentials are separate fields.
+func jobFieldInputs(data *JobResourceModel) []jobFieldInput {
+	// the launch already reported an invalid extravars_json
+	var ignored diag.Diagnostics
+	jsonFields := make(map[string]attr.Value)
+	for key := range expandExtravarsJSON(&ignored, data.ExtravarsJSON) {
+		jsonFields[key] = data.ExtravarsJSON
+	}
+
+	return []jobFieldInput{
+		{attribute: "extravars", fields: data.Extravars.Elements(), mapKeys: true},
+		{attribute: "extravars_json", fields: jsonFields},
+		{attribute: "extravars_files", fields: data.ExtravarsFiles.Elements(), mapKeys: true},
+		{attribute: "credentials", fields: data.Credentials.Elements(), mapKeys: true},
+	}
+}
+
+// reportJobFieldErrors attaches each field error to the input that set the field, or to the resource when no input sets it.
+func reportJobFieldErrors(diags *diag.Diagnostics, inputs []jobFieldInput, fieldErrors interfaces.JobFieldErrors) {
+	for _, fieldError := range fieldErrors {
+		key := strings.TrimPrefix(fieldError.Field, "extravars.")
+		reported := false
+		for _, input := range inputs {
+			if _, ok := input.fields[key]; !ok {
+				continue
+			}
+			if input.mapKeys {
+				diags.AddAttributeError(path.Root(input.attribute).AtMapKey(key), "Invalid form field value", fieldError.Message)
+			} else {
+				diags.AddAttributeError(path.Root(input.attribute), "Invalid form field value", fmt.Sprintf("field %s: %s", key, fieldError.Message))
+			}
+			reported = true
+			break
+		}
+		if !reported {
+			diags.AddError("Invalid form field value", fmt.Sprintf("field %s: %s", fieldError.Field, fieldError.Message))
+		}
+	}
 }
 
 // Read resource information.
 func (r *JobResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
 	var data *JobResourceModel
 
 	// Read Terraform prior state data into the model
@@ -237,6 +1100,8 @@ func (r *JobResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, timeoutDone := withTimeout(ctx, &resp.Diagnostics, "read", r.timeout(&resp.Diagnostics, data, "read"))
+	defer timeoutDone()
 
 	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
 
@@ -247,32 +1112,42 @@ func (r *JobResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	}
 	tflog.Debug(ctx, fmt.Sprintf("read a job resource: %#v", data))
 
-	var job *interfaces.JobGetDataSourceModel
-	if data.ID.ValueString() != "" {
-		job, err = interfaces.GetJobByID(errorHandler, *client, data.ID.ValueString())
-	} else {
+	if data.ID.ValueString() == "" {
 		return
 	}
+	job, err := interfaces.FindJobByID(errorHandler, *client, data.ID.ValueString())
 	if err != nil {
 		return
 	}
 
 	if job == nil {
+		// the job was deleted outside of Terraform, or the imported job does not exist
+		tflog.Warn(ctx, fmt.Sprintf("job %s not found, removing it from state", data.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
 	data.ID = types.StringValue(strconv.FormatInt(job.ID, 10))
+	data.JobURL = types.StringValue(r.config.providerConfig.JobURL(data.CxProfileName.ValueString(), data.ID.ValueString()))
 
-	if job.Form != "" {
+	// form_name is not set with raw_payload
+	if job.Form != "" && data.RawPayload.IsNull() {
 		data.FormName = types.StringValue(job.Form)
 	}
 	if job.Status != "" {
 		data.Status = types.StringValue(job.Status)
 	}
-	//data.Extravars = jsonStringToMapValue(ctx, &resp.Diagnostics, restInfo.JobGetDataSourceModel.Extravars)
-	//data.Credentials = jsonStringToMapValue(ctx, &resp.Diagnostics, restInfo.JobGetDataSourceModel.Credentials)
-	if job.Output != "" {
-		data.Output = types.StringValue(job.Output)
+	// extravars and credentials are only read back after an import, the server may add or reformat values.
+	// values from files or extravars_json are not read back, file values are not to be saved in the state.
+	// neither are values sent with raw_payload, as these attributes conflict with it.
+	if data.Extravars.IsNull() && data.ExtravarsFiles.IsNull() && data.ExtravarsJSON.IsNull() && data.RawPayload.IsNull() && job.Extravars != "" {
+		data.Extravars = jsonStringToMapValue(ctx, &resp.Diagnostics, job.Extravars)
+	}
+	if data.Credentials.IsNull() && data.RawPayload.IsNull() && job.Credentials != "" {
+		data.Credentials = jsonStringToMapValue(ctx, &resp.Diagnostics, job.Credentials)
+	}
+	if job.Output != "" || data.Output.IsNull() {
+		setJobOutput(&resp.Diagnostics, data, *job)
 	}
 	if job.Counter != 0 {
 		data.Counter = types.Int64Value(job.Counter)
@@ -289,9 +1164,15 @@ func (r *JobResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 	if job.End != "" {
 		data.End = types.StringValue(job.End)
 	}
+	setJobTiming(data, *job)
+	if job.Status != "" {
+		data.AwaitingApproval = types.BoolValue(job.IsAwaitingApproval())
+		data.QueuePosition = jobQueuePosition(*job)
+	}
 	if job.Approval != "" {
 		data.Approval = types.StringValue(job.Approval)
 	}
+	setJobPlaybook(data, *job)
 
 	// Write logs using the tflog package
 	// Documentation: https://terraform.io/plugin/log
@@ -303,10 +1184,147 @@ func (r *JobResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 
 // Update updates the resource and sets the updated Terraform state on success.
 func (r *JobResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var plan, state *JobResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// a new run is launched with the planned configuration, dedup_window does not apply.
+	if !plan.RerunOn.Equal(state.RerunOn) {
+		tflog.Info(ctx, fmt.Sprintf("rerun_on changed, launching a new run of job %s", state.ID.ValueString()))
+		// the new run is bounded as a create
+		ctx, timeoutDone := withTimeout(ctx, &resp.Diagnostics, "create", r.timeout(&resp.Diagnostics, plan, "create"))
+		defer timeoutDone()
+		if r.launchJob(ctx, &resp.Diagnostics, plan, false) {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		}
+		return
+	}
+
+	// these attributes are only used when launching a job, keep them in sync with the configuration.
+	state.RerunOn = plan.RerunOn
+	state.DedupWindow = plan.DedupWindow
+	state.ExtendTimeoutOnProgress = plan.ExtendTimeoutOnProgress
+	state.MaxTotalTimeout = plan.MaxTotalTimeout
+	state.MaxQueueWait = plan.MaxQueueWait
+	state.OutputMaxLength = plan.OutputMaxLength
+	state.OutputFile = plan.OutputFile
+	state.CompletionTimeout = plan.CompletionTimeout
+	state.ValidateInputs = plan.ValidateInputs
+	state.RetainOnFailure = plan.RetainOnFailure
+	state.WaitForCompletion = plan.WaitForCompletion
+	state.RetryOnFailure = plan.RetryOnFailure
+	state.RetryDelay = plan.RetryDelay
+	state.ReturnOnApprovalWait = plan.ReturnOnApprovalWait
+	state.Timeouts = plan.Timeouts
+	// a job imported by id alone does not record the profile name, it is only set in place after such an import.
+	if !plan.CxProfileName.Equal(state.CxProfileName) {
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, importedWithoutProfileKey, nil)...)
+	}
+	state.CxProfileName = plan.CxProfileName
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// ModifyPlan marks the attributes of the run as unknown when rerun_on changes, as Update launches a new run.
+func (r *JobResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	// nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan, state *JobResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// a job is only launched on create, validate its inputs now rather than when applying
+	if req.State.Raw.IsNull() {
+		r.validatePlannedInputs(ctx, &resp.Diagnostics, plan)
+		return
+	}
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.RerunOn.Equal(state.RerunOn) {
+		return
+	}
+
+	for _, name := range []string{"id", "last_updated", "status", "target", "output", "counter", "no_of_records", "start", "end", "approval", "output_lines", "output_truncated", "started_at", "finished_at", "duration_seconds", "awaiting_approval", "job_url", "playbook", "inventory", "queue_position", "idempotency_key"} {
+		var value attr.Value
+		switch name {
+		case "counter", "no_of_records", "duration_seconds", "queue_position":
+			value = types.Int64Unknown()
+		case "output_lines":
+			value = types.ListUnknown(types.StringType)
+		case "output_truncated", "awaiting_approval":
+			value = types.BoolUnknown()
+		default:
+			value = types.StringUnknown()
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), value)...)
+	}
+}
+
+// newIdempotencyKey returns a random key for the launch request of a run.
+// It is generated on apply, a random value in the plan would differ when ModifyPlan runs again at apply time.
+func newIdempotencyKey(diags *diag.Diagnostics) types.String {
+	key, err := uuid.GenerateUUID()
+	if err != nil {
+		diags.AddError("error generating idempotency_key", err.Error())
+		return types.StringUnknown()
+	}
+
+	return types.StringValue(key)
+}
+
+// validatePlannedInputs validates the inputs of a planned job when validate_inputs is set.
+// Validation is skipped while the form name or extravars are unknown, when extravars_files is set as the files are only read on create,
+// or when the provider is not configured yet.  It is done again on create.
+func (r *JobResource) validatePlannedInputs(ctx context.Context, diags *diag.Diagnostics, plan *JobResourceModel) {
+	if !plan.ValidateInputs.ValueBool() || !plan.RawPayload.IsNull() || plan.FormName.IsUnknown() || plan.CxProfileName.IsUnknown() ||
+		plan.Extravars.IsUnknown() || plan.ExtravarsJSON.IsUnknown() || !plan.ExtravarsFiles.IsNull() || len(r.config.providerConfig.ConnectionProfiles) == 0 {
+		return
+	}
+	for _, value := range plan.Extravars.Elements() {
+		if value.IsUnknown() {
+			return
+		}
+	}
+	errorHandler := utils.NewErrorHandler(ctx, diags)
+	client, err := getRestClient(errorHandler, r.config, plan.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+	var request interfaces.JobResourceModel
+	request.Form = plan.FormName.ValueString()
+	request.Extravars = mergeExtravars(expandExtravarsJSON(diags, plan.ExtravarsJSON), expandExtravars(ctx, diags, plan.Extravars))
+	// credentials are checked on create when they are not known yet
+	credentialsKnown := !plan.Credentials.IsUnknown()
+	for _, value := range plan.Credentials.Elements() {
+		credentialsKnown = credentialsKnown && !value.IsUnknown()
+	}
+	if credentialsKnown {
+		request.Credentials = expandExtravars(ctx, diags, plan.Credentials)
+	}
+	if diags.HasError() {
+		return
+	}
+	validateJobInputs(errorHandler, diags, *client, request)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 func (r *JobResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
 	var data *JobResourceModel
 
 	// Read Terraform prior state data into the model
@@ -314,6 +1332,8 @@ func (r *JobResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	ctx, timeoutDone := withTimeout(ctx, &resp.Diagnostics, "delete", r.timeout(&resp.Diagnostics, data, "delete"))
+	defer timeoutDone()
 
 	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
 	if data.ID.IsNull() {
@@ -330,8 +1350,73 @@ func (r *JobResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 		// error reporting done inside NewClient
 		return
 	}
+	if data.RetainOnFailure.ValueBool() {
+		job, err := interfaces.FindJobByID(errorHandler, *client, data.ID.ValueString())
+		if err != nil {
+			return
+		}
+		if job != nil && interfaces.IsJobFailed(job.Status) {
+			tflog.Info(ctx, fmt.Sprintf("retain_on_failure is set, keeping job %s with status %s on the server", data.ID.ValueString(), job.Status))
+			return
+		}
+	}
+	waitOptions := interfaces.JobWaitOptions{
+		Timeout:            r.completionTimeout(data),
+		PollInterval:       jobPollInterval,
+		MaxPollInterval:    jobMaxPollInterval,
+		MaxTransientErrors: r.config.providerConfig.JobPollMaxTransientErrors,
+		LongPollTimeout:    r.config.providerConfig.longPollTimeout(),
+	}
+	// the job stays in state until it is no longer running, error reporting done inside CancelJobByID
+	err = interfaces.CancelJobByID(errorHandler, *client, data.ID.ValueString(), waitOptions)
+	if err != nil {
+		return
+	}
 	err = interfaces.DeleteJobByID(errorHandler, *client, data.ID.ValueString())
 	if err != nil {
 		return
 	}
 }
+
+// ImportState imports a job, the import ID is <id> or <cx_profile_name>,<id>.
+// Read removes a job that does not exist, and Terraform then fails the import with "Cannot import non-existent remote object".
+func (r *JobResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	id := req.ID
+	if cxProfileName, jobID, found := strings.Cut(req.ID, ","); found {
+		if cxProfileName == "" {
+			resp.Diagnostics.AddError("invalid import ID",
+				fmt.Sprintf("Expected <id> or <cx_profile_name>,<id>, got %q.", req.ID))
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cx_profile_name"), cxProfileName)...)
+		id = jobID
+	} else {
+		// the profile of the job is not known, setting cx_profile_name afterwards does not launch a new job
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, importedWithoutProfileKey, []byte("true"))...)
+	}
+	jobID, err := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+	if err != nil {
+		resp.Diagnostics.AddError("invalid import ID",
+			fmt.Sprintf("Expected a numeric job id, got %q.", id))
+		return
+	}
+	// the id is saved as formatted by Read, so that "042" and "42" import the same job without a diff
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), strconv.FormatInt(jobID, 10))...)
+}
+
+// importedWithoutProfileKey is the private state key set when a job is imported by id alone, until cx_profile_name is set.
+const importedWithoutProfileKey = "imported_without_profile"
+
+// cxProfileNameRequiresReplace launches a new job when cx_profile_name changes, except when it is set after an import by id alone,
+// as the state then has no profile name.
+func cxProfileNameRequiresReplace(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+	resp.RequiresReplace = !(req.StateValue.IsNull() && importedWithoutProfile(ctx, &resp.Diagnostics, req.Private))
+}
+
+// importedWithoutProfile reports whether the job was imported by id alone, and cx_profile_name was not set since.
+func importedWithoutProfile(ctx context.Context, diags *diag.Diagnostics, private privateState) bool {
+	value, d := private.GetKey(ctx, importedWithoutProfileKey)
+	diags.Append(d...)
+
+	return string(value) == "true"
+}
@@ -1,12 +1,32 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/restclient"
+	"terraform-provider-ansible-forms/internal/utils"
 )
 
 func TestAccJobResource(t *testing.T) {
@@ -42,17 +62,104 @@ func TestAccJobResource(t *testing.T) {
 	})
 }
 
+func TestAccJobResource_token(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccTokenPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccJobResourceTokenConfig("Demo Form Ansible No input"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_job_resource.job", "form_name", "Demo Form Ansible No input"),
+					resource.TestCheckResourceAttrSet("ansible-forms_job_resource.job", "id")),
+			},
+		},
+	})
+}
+
+func TestAccJobResource_noWait(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccJobResourceNoWaitConfig("Demo Form Ansible No input"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("ansible-forms_job_resource.job", "id"),
+					resource.TestCheckResourceAttrSet("ansible-forms_job_resource.job", "status")),
+			},
+			{
+				// the job completes in a few seconds, refresh reads its final status and output
+				PreConfig:    func() { time.Sleep(30 * time.Second) },
+				RefreshState: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_job_resource.job", "status", "success"),
+					resource.TestCheckResourceAttrSet("ansible-forms_job_resource.job", "finished_at"),
+					resource.TestCheckResourceAttrSet("ansible-forms_job_resource.job", "output")),
+			},
+		},
+	})
+}
+
+func testAccJobResourceNoWaitConfig(jobFormName string) string {
+	// environment variables are checked in testAccPreCheck
+	host := os.Getenv("TF_ACC_ANSIBLE_FORMS_HOST")
+	admin := os.Getenv("TF_ACC_ANSIBLE_FORMS_USER")
+	password := os.Getenv("TF_ACC_ANSIBLE_FORMS_PASS")
+	return fmt.Sprintf(`
+provider "ansible-forms" {
+ connection_profiles = [
+    {
+      name = "cluster4"
+      hostname = "%s"
+      username = "%s"
+      password = "%s"
+      validate_certs = false
+    },
+  ]
+}
+
+resource "ansible-forms_job_resource" "job" {
+  cx_profile_name     = "cluster4"
+  form_name           = "%s"
+  wait_for_completion = false
+  extravars           = {}
+  credentials         = {}
+}`, host, admin, password, jobFormName)
+}
+
+func testAccJobResourceTokenConfig(jobFormName string) string {
+	// environment variables are checked in testAccTokenPreCheck
+	host := os.Getenv("TF_ACC_ANSIBLE_FORMS_HOST")
+	token := os.Getenv("TF_ACC_ANSIBLE_FORMS_TOKEN")
+	return fmt.Sprintf(`
+provider "ansible-forms" {
+ connection_profiles = [
+    {
+      name = "cluster4"
+      hostname = "%s"
+      token = "%s"
+      validate_certs = false
+    },
+  ]
+}
+
+resource "ansible-forms_job_resource" "job" {
+  cx_profile_name = "cluster4"
+  form_name       = "%s"
+  extravars       = {}
+  credentials     = {}
+}`, host, token, jobFormName)
+}
+
 func testAccJobResourceConfig(jobFormName string) string {
+	// environment variables are checked in testAccPreCheck
 	host := os.Getenv("TF_ACC_ANSIBLE_FORMS_HOST")
 	//host := "127.0.0.1:8443"
 	admin := os.Getenv("TF_ACC_ANSIBLE_FORMS_USER")
 	//admin := "admin"
 	password := os.Getenv("TF_ACC_ANSIBLE_FORMS_PASS")
 	//password := "AnsibleForms!123"
-	if host == "" || admin == "" || password == "" {
-		fmt.Println("TF_ACC_ANSIBLE_FORMS_HOST, TF_ACC_ANSIBLE_FORMS_USER, and TF_ACC_ANSIBLE_FORMS_PASS must be set for acceptance tests")
-		os.Exit(1)
-	}
 	return fmt.Sprintf(`
 provider "ansible-forms" {
  connection_profiles = [
@@ -88,3 +195,646 @@ resource "ansible-forms_job_resource" "job" {
   }
 }`, host, admin, password, jobFormName)
 }
+
+// jobResourceValue returns a job resource object with null attributes, except for values.
+func jobResourceValue(ctx context.Context, r *JobResource, values map[string]tftypes.Value) (fwresource.SchemaResponse, tftypes.Value) {
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	attributes := map[string]tftypes.Value{}
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	for name, value := range values {
+		attributes[name] = value
+	}
+
+	return schemaResp, tftypes.NewValue(objectType, attributes)
+}
+
+func TestJobResource_ValidateConfig(t *testing.T) {
+	tests := []struct {
+		name              string
+		completionTimeout tftypes.Value
+		maxQueueWait      tftypes.Value
+		wantErr           bool
+	}{
+		{name: "unset", completionTimeout: tftypes.NewValue(tftypes.Number, nil)},
+		{name: "positive", completionTimeout: tftypes.NewValue(tftypes.Number, 2400)},
+		{name: "unknown", completionTimeout: tftypes.NewValue(tftypes.Number, tftypes.UnknownValue)},
+		{name: "zero", completionTimeout: tftypes.NewValue(tftypes.Number, 0), wantErr: true},
+		{name: "negative", completionTimeout: tftypes.NewValue(tftypes.Number, -1), wantErr: true},
+		{name: "max_queue_wait", completionTimeout: tftypes.NewValue(tftypes.Number, nil), maxQueueWait: tftypes.NewValue(tftypes.Number, 300)},
+		{name: "max_queue_wait_zero", completionTimeout: tftypes.NewValue(tftypes.Number, nil), maxQueueWait: tftypes.NewValue(tftypes.Number, 0), wantErr: true},
+	}
+	ctx := context.Background()
+	r := NewJobResource().(*JobResource)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]tftypes.Value{"form_name": tftypes.NewValue(tftypes.String, "demo"), "completion_timeout": tt.completionTimeout}
+			if tt.maxQueueWait.Type() != nil {
+				values["max_queue_wait"] = tt.maxQueueWait
+			}
+			schemaResp, config := jobResourceValue(ctx, r, values)
+			req := fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(ctx, req, &resp)
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("ValidateConfig() diagnostics = %v, wantErr %v", resp.Diagnostics, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobResource_ValidateConfig_rawPayload(t *testing.T) {
+	formName := tftypes.NewValue(tftypes.String, "demo")
+	credentials := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{"ontap_cred": tftypes.NewValue(tftypes.String, "cluster1_admin")})
+	tests := []struct {
+		name      string
+		values    map[string]tftypes.Value
+		wantPaths []string
+	}{
+		{name: "form_name", values: map[string]tftypes.Value{"form_name": formName, "credentials": credentials}},
+		{name: "raw_payload", values: map[string]tftypes.Value{"raw_payload": tftypes.NewValue(tftypes.String, `{"formName": "demo", "extravars": {"size": 10}}`)}},
+		{name: "unknown_raw_payload", values: map[string]tftypes.Value{"raw_payload": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}},
+		{name: "neither", values: map[string]tftypes.Value{"credentials": credentials}, wantPaths: []string{"form_name"}},
+		{name: "not_an_object", values: map[string]tftypes.Value{"raw_payload": tftypes.NewValue(tftypes.String, `[1]`)}, wantPaths: []string{"raw_payload"}},
+		{name: "conflicts", values: map[string]tftypes.Value{
+			"raw_payload": tftypes.NewValue(tftypes.String, `{}`),
+			"form_name":   formName,
+			"extravars":   tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{"size": tftypes.NewValue(tftypes.String, "10")}),
+			"credentials": credentials,
+			"limit":       tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}, wantPaths: []string{"form_name", "extravars", "credentials", "limit"}},
+	}
+	ctx := context.Background()
+	r := NewJobResource().(*JobResource)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaResp, config := jobResourceValue(ctx, r, tt.values)
+			req := fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(ctx, req, &resp)
+			var got []string
+			for _, d := range resp.Diagnostics.Errors() {
+				if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+					got = append(got, withPath.Path().String())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantPaths) {
+				t.Errorf("ValidateConfig() error paths = %v, want %v, diagnostics %v", got, tt.wantPaths, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestJobResource_ValidateConfig_timeouts(t *testing.T) {
+	timeoutsType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"create": tftypes.String, "read": tftypes.String, "delete": tftypes.String}}
+	timeouts := func(create string, read string) tftypes.Value {
+		values := map[string]tftypes.Value{
+			"create": tftypes.NewValue(tftypes.String, nil),
+			"read":   tftypes.NewValue(tftypes.String, nil),
+			"delete": tftypes.NewValue(tftypes.String, nil),
+		}
+		if create != "" {
+			values["create"] = tftypes.NewValue(tftypes.String, create)
+		}
+		if read != "" {
+			values["read"] = tftypes.NewValue(tftypes.String, read)
+		}
+		return tftypes.NewValue(timeoutsType, values)
+	}
+	tests := []struct {
+		name      string
+		timeouts  tftypes.Value
+		wantPaths []string
+	}{
+		{name: "unset", timeouts: tftypes.NewValue(timeoutsType, nil)},
+		{name: "valid", timeouts: timeouts("45m", "30s")},
+		{name: "create_too_short", timeouts: timeouts("5s", ""), wantPaths: []string{`timeouts.create`}},
+		{name: "not_a_duration", timeouts: timeouts("", "10"), wantPaths: []string{`timeouts.read`}},
+		{name: "negative", timeouts: timeouts("-1m", ""), wantPaths: []string{`timeouts.create`}},
+	}
+	ctx := context.Background()
+	r := NewJobResource().(*JobResource)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schemaResp, config := jobResourceValue(ctx, r, map[string]tftypes.Value{
+				"form_name": tftypes.NewValue(tftypes.String, "demo"),
+				"timeouts":  tt.timeouts,
+			})
+			req := fwresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: config}}
+			var resp fwresource.ValidateConfigResponse
+			r.ValidateConfig(ctx, req, &resp)
+			var got []string
+			for _, d := range resp.Diagnostics.Errors() {
+				if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+					got = append(got, withPath.Path().String())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantPaths) {
+				t.Errorf("ValidateConfig() error paths = %v, want %v, diagnostics %v", got, tt.wantPaths, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestJobResource_completionTimeout(t *testing.T) {
+	timeouts := types.ObjectValueMust(map[string]attr.Type{"create": types.StringType, "read": types.StringType, "delete": types.StringType},
+		map[string]attr.Value{"create": types.StringValue("45m"), "read": types.StringNull(), "delete": types.StringNull()})
+	tests := []struct {
+		name string
+		data JobResourceModel
+		want time.Duration
+	}{
+		{name: "provider", data: JobResourceModel{Timeouts: types.ObjectNull(timeouts.AttributeTypes(context.Background()))}, want: 10 * time.Minute},
+		{name: "create", data: JobResourceModel{Timeouts: timeouts}, want: 45 * time.Minute},
+		{name: "completion_timeout", data: JobResourceModel{Timeouts: timeouts, CompletionTimeout: types.Int64Value(60)}, want: time.Minute},
+	}
+	r := NewJobResource().(*JobResource)
+	r.config.providerConfig.JobCompletionTimeOut = 600
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.completionTimeout(&tt.data); got != tt.want {
+				t.Errorf("completionTimeout() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobResource_ModifyPlan(t *testing.T) {
+	tests := []struct {
+		name         string
+		create       bool
+		planRerunOn  string
+		wantNewRunID bool
+	}{
+		{name: "create", create: true, planRerunOn: "1", wantNewRunID: true},
+		{name: "same_rerun_on", planRerunOn: "1"},
+		{name: "new_rerun_on", planRerunOn: "2", wantNewRunID: true},
+	}
+	ctx := context.Background()
+	r := NewJobResource().(*JobResource)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputLines := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{tftypes.NewValue(tftypes.String, "ok")})
+			schemaResp, state := jobResourceValue(ctx, r, map[string]tftypes.Value{
+				"id":              tftypes.NewValue(tftypes.String, "7"),
+				"rerun_on":        tftypes.NewValue(tftypes.String, "1"),
+				"output_lines":    outputLines,
+				"idempotency_key": tftypes.NewValue(tftypes.String, "key-1"),
+			})
+			_, plan := jobResourceValue(ctx, r, map[string]tftypes.Value{
+				"id":              tftypes.NewValue(tftypes.String, "7"),
+				"rerun_on":        tftypes.NewValue(tftypes.String, tt.planRerunOn),
+				"output_lines":    outputLines,
+				"idempotency_key": tftypes.NewValue(tftypes.String, "key-1"),
+			})
+			if tt.create {
+				state = tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)
+				_, plan = jobResourceValue(ctx, r, map[string]tftypes.Value{
+					"id":              tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+					"rerun_on":        tftypes.NewValue(tftypes.String, tt.planRerunOn),
+					"output_lines":    tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+					"idempotency_key": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+				})
+			}
+			req := fwresource.ModifyPlanRequest{
+				State: tfsdk.State{Schema: schemaResp.Schema, Raw: state},
+				Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+			}
+			resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("ModifyPlan() diagnostics = %v", resp.Diagnostics)
+			}
+			var data JobResourceModel
+			if diags := resp.Plan.Get(ctx, &data); diags.HasError() {
+				t.Fatalf("Plan.Get() diagnostics = %v", diags)
+			}
+			if data.ID.IsUnknown() != tt.wantNewRunID || data.OutputLines.IsUnknown() != tt.wantNewRunID {
+				t.Errorf("ModifyPlan() id = %v, output_lines = %v, want unknown %v", data.ID, data.OutputLines, tt.wantNewRunID)
+			}
+			// the key of a new run is generated on apply
+			if data.IdempotencyKey.IsUnknown() != tt.wantNewRunID {
+				t.Errorf("ModifyPlan() idempotency_key = %v, want unknown %v", data.IdempotencyKey, tt.wantNewRunID)
+			}
+
+			// Terraform plans the same proposed values again at apply time, the final plan must match the first one.
+			again := fwresource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(ctx, req, &again)
+			if again.Diagnostics.HasError() {
+				t.Fatalf("second ModifyPlan() diagnostics = %v", again.Diagnostics)
+			}
+			if !again.Plan.Raw.Equal(resp.Plan.Raw) {
+				t.Errorf("second ModifyPlan() = %v, want %v", again.Plan.Raw, resp.Plan.Raw)
+			}
+		})
+	}
+}
+
+func TestJobResource_ModifyPlan_validateInputs(t *testing.T) {
+	forms := []any{map[string]any{
+		"name": "demo",
+		"fields": []any{
+			map[string]any{"name": "vm_name", "type": "text", "required": true},
+			map[string]any{"name": "size", "type": "enum", "values": []any{"small", "large"}},
+		},
+	}}
+	envelope := map[string]any{"status": "success", "message": "forms loaded", "data": forms}
+	response := restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "form", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{envelope}}}
+	credentialsEnvelope := map[string]any{"status": "success", "message": "credentials loaded", "data": []any{map[string]any{"id": float64(1), "name": "cluster1_admin"}}}
+	credentialsResponse := restclient.MockResponse{ExpectedMethod: "GET", ExpectedURL: "credential", StatusCode: 200, Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{credentialsEnvelope}}}
+	validExtravars := map[string]tftypes.Value{
+		"vm_name": tftypes.NewValue(tftypes.String, "vm1"),
+	}
+
+	tests := []struct {
+		name           string
+		validateInputs bool
+		extravars      map[string]tftypes.Value
+		credentials    map[string]tftypes.Value
+		wantPaths      []path.Path
+	}{
+		{name: "valid", validateInputs: true, extravars: map[string]tftypes.Value{
+			"vm_name": tftypes.NewValue(tftypes.String, "vm1"),
+			"size":    tftypes.NewValue(tftypes.String, "small"),
+		}},
+		{name: "invalid", validateInputs: true, extravars: map[string]tftypes.Value{
+			"size": tftypes.NewValue(tftypes.String, "medium"),
+		}, wantPaths: []path.Path{path.Root("extravars"), path.Root("extravars").AtMapKey("size")}},
+		{name: "unknown", validateInputs: true, extravars: map[string]tftypes.Value{
+			"size": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		}},
+		{name: "not_validated", extravars: map[string]tftypes.Value{
+			"size": tftypes.NewValue(tftypes.String, "medium"),
+		}},
+		{name: "known_credential", validateInputs: true, extravars: validExtravars, credentials: map[string]tftypes.Value{
+			"ontap_cred": tftypes.NewValue(tftypes.String, "cluster1_admin"),
+		}},
+		{name: "unknown_credential", validateInputs: true, extravars: validExtravars, credentials: map[string]tftypes.Value{
+			"ontap_cred": tftypes.NewValue(tftypes.String, "cluster1_admin"),
+			"bind_cred":  tftypes.NewValue(tftypes.String, "ldap"),
+		}, wantPaths: []path.Path{path.Root("credentials").AtMapKey("bind_cred")}},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := []restclient.MockResponse{response}
+			if len(tt.credentials) != 0 {
+				responses = append(responses, credentialsResponse)
+			}
+			client, err := restclient.NewMockedRestClient(responses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := NewJobResource().(*JobResource)
+			r.config.client = client
+			r.config.providerConfig.ConnectionProfiles = map[string]ConnectionProfile{"cluster1": {}}
+			schemaResp, state := jobResourceValue(ctx, r, nil)
+			_, plan := jobResourceValue(ctx, r, map[string]tftypes.Value{
+				"form_name":       tftypes.NewValue(tftypes.String, "demo"),
+				"validate_inputs": tftypes.NewValue(tftypes.Bool, tt.validateInputs),
+				"extravars":       tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tt.extravars),
+				"credentials":     tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tt.credentials),
+			})
+			req := fwresource.ModifyPlanRequest{
+				State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(state.Type(), nil)},
+				Plan:  tfsdk.Plan{Schema: schemaResp.Schema, Raw: plan},
+			}
+			resp := fwresource.ModifyPlanResponse{Plan: req.Plan}
+			r.ModifyPlan(ctx, req, &resp)
+			var gotPaths []path.Path
+			for _, d := range resp.Diagnostics.Errors() {
+				if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+					gotPaths = append(gotPaths, withPath.Path())
+				} else {
+					t.Errorf("ModifyPlan() unexpected diagnostic %v", d)
+				}
+			}
+			if !reflect.DeepEqual(gotPaths, tt.wantPaths) {
+				t.Errorf("ModifyPlan() error paths = %v, want %v", gotPaths, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestSetJobTiming(t *testing.T) {
+	tests := []struct {
+		name           string
+		job            interfaces.JobGetDataSourceModel
+		wantStartedAt  types.String
+		wantFinishedAt types.String
+		wantDuration   types.Int64
+	}{
+		{name: "success",
+			job:           interfaces.JobGetDataSourceModel{Status: "success", Start: "2024-05-01 10:00:00", End: "2024-05-01 10:02:05"},
+			wantStartedAt: types.StringValue("2024-05-01T10:00:00Z"), wantFinishedAt: types.StringValue("2024-05-01T10:02:05Z"), wantDuration: types.Int64Value(125)},
+		{name: "rfc3339",
+			job:           interfaces.JobGetDataSourceModel{Status: "failed", Start: "2024-05-01T12:00:00+02:00", End: "2024-05-01T10:00:30Z"},
+			wantStartedAt: types.StringValue("2024-05-01T10:00:00Z"), wantFinishedAt: types.StringValue("2024-05-01T10:00:30Z"), wantDuration: types.Int64Value(30)},
+		{name: "running",
+			job:           interfaces.JobGetDataSourceModel{Status: "running", Start: "2024-05-01 10:00:00"},
+			wantStartedAt: types.StringValue("2024-05-01T10:00:00Z"), wantFinishedAt: types.StringNull(), wantDuration: types.Int64Null()},
+		{name: "omitted",
+			job:           interfaces.JobGetDataSourceModel{Status: "success"},
+			wantStartedAt: types.StringNull(), wantFinishedAt: types.StringNull(), wantDuration: types.Int64Null()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data JobResourceModel
+			setJobTiming(&data, tt.job)
+			if !data.StartedAt.Equal(tt.wantStartedAt) || !data.FinishedAt.Equal(tt.wantFinishedAt) || !data.DurationSeconds.Equal(tt.wantDuration) {
+				t.Errorf("setJobTiming() = %s, %s, %s, want %s, %s, %s", data.StartedAt, data.FinishedAt, data.DurationSeconds, tt.wantStartedAt, tt.wantFinishedAt, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestSetJobPlaybook(t *testing.T) {
+	var data JobResourceModel
+	setJobPlaybook(&data, interfaces.JobGetDataSourceModel{Status: "success", Playbook: "site.yml", Inventory: "production"})
+	if !data.Playbook.Equal(types.StringValue("site.yml")) || !data.Inventory.Equal(types.StringValue("production")) {
+		t.Errorf("setJobPlaybook() = %s, %s, want site.yml, production", data.Playbook, data.Inventory)
+	}
+	setJobPlaybook(&data, interfaces.JobGetDataSourceModel{Status: "success"})
+	if !data.Playbook.IsNull() || !data.Inventory.IsNull() {
+		t.Errorf("setJobPlaybook() = %s, %s, want null when omitted", data.Playbook, data.Inventory)
+	}
+}
+
+func TestReadExtravarsFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "id_rsa")
+	if err := os.WriteFile(keyFile, []byte("-----BEGIN KEY-----\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missingFile := filepath.Join(dir, "missing")
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		files     map[string]attr.Value
+		extravars map[string]any
+		want      map[string]any
+		wantErr   bool
+	}{
+		{name: "none"},
+		{name: "file", files: map[string]attr.Value{"ssh_key": types.StringValue(keyFile)}, want: map[string]any{"ssh_key": "-----BEGIN KEY-----\n"}},
+		{name: "extravars_first", files: map[string]attr.Value{"ssh_key": types.StringValue(keyFile)},
+			extravars: map[string]any{"ssh_key": "inline", "vm_name": "vm1"}, want: map[string]any{"ssh_key": "inline", "vm_name": "vm1"}},
+		{name: "unreadable", files: map[string]attr.Value{"ssh_key": types.StringValue(missingFile)}, want: map[string]any{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := types.MapNull(types.StringType)
+			if tt.files != nil {
+				files = types.MapValueMust(types.StringType, tt.files)
+			}
+			var diags diag.Diagnostics
+			got := mergeExtravars(readExtravarsFiles(ctx, &diags, files), tt.extravars)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("readExtravarsFiles() diagnostics = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if tt.wantErr && !strings.Contains(diags.Errors()[0].Detail(), missingFile) {
+				t.Errorf("readExtravarsFiles() detail = %q, want path %s", diags.Errors()[0].Detail(), missingFile)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeExtravars() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteJobOutputFile(t *testing.T) {
+	const output = "line 1\nline 2\nline 3\n"
+	tests := []struct {
+		name          string
+		outputFile    bool
+		status        string
+		maxLength     int64
+		wantWritten   bool
+		wantOutput    string
+		wantTruncated bool
+	}{
+		{name: "not_set", status: "success"},
+		{name: "running", outputFile: true, status: "running"},
+		{name: "whole_output", outputFile: true, status: "success", wantWritten: true, wantOutput: output},
+		{name: "truncated", outputFile: true, status: "failed", maxLength: 7, wantWritten: true, wantOutput: "line 1\n", wantTruncated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "job.log")
+			data := &JobResourceModel{OutputFile: types.StringNull(), OutputMaxLength: types.Int64Null()}
+			if tt.outputFile {
+				data.OutputFile = types.StringValue(name)
+			}
+			if tt.maxLength != 0 {
+				data.OutputMaxLength = types.Int64Value(tt.maxLength)
+			}
+			response := restclient.RestResponse{NumRecords: 1, Records: []map[string]any{{"id": 1, "status": tt.status, "output": output}}}
+			client, err := restclient.NewMockedRestClient([]restclient.MockResponse{{ExpectedMethod: "GET", ExpectedURL: "job/1/output", StatusCode: 200, Response: response}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var diags diag.Diagnostics
+			errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+			job := interfaces.JobGetDataSourceModel{ID: 1, Status: tt.status}
+			if got := writeJobOutputFile(&diags, errorHandler, *client, data, job); got != tt.wantWritten {
+				t.Fatalf("writeJobOutputFile() = %v, want %v, diagnostics %v", got, tt.wantWritten, diags)
+			}
+			if diags.HasError() || diags.WarningsCount() > 0 != tt.wantTruncated {
+				t.Errorf("writeJobOutputFile() diagnostics = %v, want a warning %v", diags, tt.wantTruncated)
+			}
+			content, err := os.ReadFile(name)
+			if !tt.wantWritten {
+				if err == nil {
+					t.Errorf("writeJobOutputFile() wrote %q, want no file", content)
+				}
+				return
+			}
+			if string(content) != output {
+				t.Errorf("output file = %q, %v, want %q", content, err, output)
+			}
+			if data.Output.ValueString() != tt.wantOutput || data.OutputTruncated.ValueBool() != tt.wantTruncated {
+				t.Errorf("output = %q, truncated %v, want %q, %v", data.Output.ValueString(), data.OutputTruncated.ValueBool(), tt.wantOutput, tt.wantTruncated)
+			}
+			if entries, _ := os.ReadDir(filepath.Dir(name)); len(entries) != 1 {
+				t.Errorf("output directory has %d files, want only the output file", len(entries))
+			}
+		})
+	}
+}
+
+func TestJobResource_retryFailedJob(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		retries    types.Int64
+		retryDelay types.Int64
+		attempt    int64
+		deadline   time.Duration
+		want       bool
+	}{
+		{name: "no_retries", ctx: context.Background(), retries: types.Int64Null(), retryDelay: types.Int64Value(0), attempt: 1, deadline: time.Minute},
+		{name: "retry", ctx: context.Background(), retries: types.Int64Value(2), retryDelay: types.Int64Value(0), attempt: 2, deadline: time.Minute, want: true},
+		{name: "exhausted", ctx: context.Background(), retries: types.Int64Value(2), retryDelay: types.Int64Value(0), attempt: 3, deadline: time.Minute},
+		{name: "past_deadline", ctx: context.Background(), retries: types.Int64Value(2), retryDelay: types.Int64Null(), attempt: 1, deadline: 10 * time.Second},
+		{name: "canceled", ctx: canceled, retries: types.Int64Value(2), retryDelay: types.Int64Value(1), attempt: 1, deadline: time.Minute},
+	}
+	r := NewJobResource().(*JobResource)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &JobResourceModel{RetryOnFailure: tt.retries, RetryDelay: tt.retryDelay}
+			job := &interfaces.GetJobResponse{Data: interfaces.JobGetDataSourceModel{ID: 7, Status: "failed"}}
+			if got := r.retryFailedJob(tt.ctx, data, job, tt.attempt, time.Now().Add(tt.deadline)); got != tt.want {
+				t.Errorf("retryFailedJob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportJobFieldErrors(t *testing.T) {
+	// a job launch rejected with field errors, as CreateJob receives it
+	rejected := restclient.MockResponse{ExpectedMethod: "POST", ExpectedURL: "job/", StatusCode: 400, Err: fmt.Errorf("statusCode 400"),
+		Response: restclient.RestResponse{NumRecords: 1, Records: []map[string]any{{"status": "error", "errors": []any{
+			map[string]any{"field": "extravars.size", "message": "must be a number"},
+			map[string]any{"field": "owner", "message": "unknown user"},
+			map[string]any{"field": "ontap_cred", "message": "not allowed"},
+			map[string]any{"field": "zone", "message": "required"},
+		}}}}}
+	r, err := restclient.NewMockedRestClient([]restclient.MockResponse{rejected})
+	if err != nil {
+		panic(err)
+	}
+	var diags diag.Diagnostics
+	errorHandler := utils.NewErrorHandler(context.Background(), &diags)
+	_, err = interfaces.CreateJob(errorHandler, *r, interfaces.JobResourceModel{Form: "demo"})
+	var fieldErrors interfaces.JobFieldErrors
+	if !errors.As(err, &fieldErrors) {
+		t.Fatalf("CreateJob() error = %v, want field errors", err)
+	}
+
+	data := &JobResourceModel{
+		Extravars:      types.MapValueMust(types.StringType, map[string]attr.Value{"size": types.StringValue("ten")}),
+		ExtravarsJSON:  types.StringValue(`{"owner": {"name": "alice"}}`),
+		ExtravarsFiles: types.MapNull(types.StringType),
+		Credentials:    types.MapValueMust(types.StringType, map[string]attr.Value{"ontap_cred": types.StringValue("cluster1_admin")}),
+	}
+	reportJobFieldErrors(&diags, jobFieldInputs(data), fieldErrors)
+	var got []string
+	for _, d := range diags {
+		if withPath, ok := d.(diag.DiagnosticWithPath); ok {
+			got = append(got, withPath.Path().String())
+			continue
+		}
+		got = append(got, "resource: "+d.Detail())
+	}
+	want := []string{`extravars["size"]`, "extravars_json", `credentials["ontap_cred"]`, "resource: field zone: required"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reportJobFieldErrors() = %v, want %v", got, want)
+	}
+}
+
+func TestExpandExtravarsJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   types.String
+		want    map[string]any
+		wantErr bool
+	}{
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "typed", value: types.StringValue(`{"enabled":true,"count":3,"disks":[{"size":10}],"owner":{"name":"alice"}}`), want: map[string]any{
+			"enabled": true,
+			"count":   json.Number("3"),
+			"disks":   []any{map[string]any{"size": json.Number("10")}},
+			"owner":   map[string]any{"name": "alice"},
+		}},
+		{name: "empty", value: types.StringValue(`{}`), want: map[string]any{}},
+		{name: "list", value: types.StringValue(`[1, 2]`), wantErr: true},
+		{name: "json_null", value: types.StringValue(`null`), wantErr: true},
+		{name: "trailing", value: types.StringValue(`{"a":1} {"b":2}`), wantErr: true},
+		{name: "invalid", value: types.StringValue(`{"a":`), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			got := expandExtravarsJSON(&diags, tt.value)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("expandExtravarsJSON() diagnostics = %v, wantErr %v", diags, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expandExtravarsJSON() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// mapPrivateState is a privateState kept in a map.
+type mapPrivateState map[string][]byte
+
+func (p mapPrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p mapPrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	if len(value) == 0 {
+		delete(p, key)
+		return nil
+	}
+	p[key] = value
+	return nil
+}
+
+func TestSetJobOutput_truncationWarning(t *testing.T) {
+	job := interfaces.JobGetDataSourceModel{ID: 1, Status: "success", Output: "line 1\nline 2\n"}
+	data := &JobResourceModel{OutputMaxLength: types.Int64Value(7), OutputTruncated: types.BoolNull()}
+	// launch, then Read of the output already truncated in state
+	for i, wantWarnings := range []int{1, 0} {
+		var diags diag.Diagnostics
+		setJobOutput(&diags, data, job)
+		if diags.WarningsCount() != wantWarnings || !data.OutputTruncated.ValueBool() {
+			t.Errorf("setJobOutput() call %d diagnostics = %v, truncated %v, want %d warnings", i+1, diags, data.OutputTruncated, wantWarnings)
+		}
+	}
+}
+
+func TestImportedWithoutProfile(t *testing.T) {
+	ctx := context.Background()
+	var diags diag.Diagnostics
+	private := mapPrivateState{}
+	if importedWithoutProfile(ctx, &diags, private) {
+		t.Errorf("importedWithoutProfile() = true, want false without the key")
+	}
+	private.SetKey(ctx, importedWithoutProfileKey, []byte("true"))
+	if !importedWithoutProfile(ctx, &diags, private) {
+		t.Errorf("importedWithoutProfile() = false, want true after an import by id")
+	}
+	private.SetKey(ctx, importedWithoutProfileKey, nil)
+	if importedWithoutProfile(ctx, &diags, private) {
+		t.Errorf("importedWithoutProfile() = true, want false once the profile is set")
+	}
+}
+
+func TestCxProfileNameRequiresReplace(t *testing.T) {
+	tests := []struct {
+		name       string
+		stateValue types.String
+		want       bool
+	}{
+		{name: "changed", stateValue: types.StringValue("cluster1"), want: true},
+		{name: "set_without_import", stateValue: types.StringNull(), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.StringRequest{StateValue: tt.stateValue, PlanValue: types.StringValue("cluster2")}
+			var resp stringplanmodifier.RequiresReplaceIfFuncResponse
+			cxProfileNameRequiresReplace(context.Background(), req, &resp)
+			if resp.RequiresReplace != tt.want {
+				t.Errorf("cxProfileNameRequiresReplace() = %v, want %v", resp.RequiresReplace, tt.want)
+			}
+		})
+	}
+}
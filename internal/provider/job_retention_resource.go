@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &JobRetentionResource{}
+	_ resource.ResourceWithConfigure      = &JobRetentionResource{}
+	_ resource.ResourceWithValidateConfig = &JobRetentionResource{}
+)
+
+// NewJobRetentionResource is a helper function to simplify the provider implementation.
+func NewJobRetentionResource() resource.Resource {
+	return &JobRetentionResource{
+		config: resourceOrDataSourceConfig{
+			name: "job_retention_resource",
+		},
+	}
+}
+
+// JobRetentionResource deletes the jobs that completed more than a number of days ago.
+type JobRetentionResource struct {
+	config resourceOrDataSourceConfig
+}
+
+// JobRetentionResourceModel maps the resource schema data.
+type JobRetentionResourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	ID            types.String `tfsdk:"id"`
+	RetentionDays types.Int64  `tfsdk:"retention_days"`
+	// PlanOnly counts the jobs to delete without deleting them.
+	PlanOnly types.Bool `tfsdk:"plan_only"`
+	// RerunOn runs the cleanup again when it changes.
+	RerunOn types.String `tfsdk:"rerun_on"`
+	// MatchedCount and DeletedCount report the last cleanup, LastRun is when it ran.
+	MatchedCount types.Int64  `tfsdk:"matched_count"`
+	DeletedCount types.Int64  `tfsdk:"deleted_count"`
+	LastRun      types.String `tfsdk:"last_run"`
+}
+
+// Metadata returns the resource type name.
+func (r *JobRetentionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.config.name
+}
+
+// Schema defines the schema for the resource.
+func (r *JobRetentionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Deletes the jobs that completed more than `retention_days` ago, whether or not Terraform tracks them. " +
+			"The cleanup runs when the resource is created or updated, refreshing the resource does not contact the server. " +
+			"Change `rerun_on` to run it again. Jobs still running are never deleted. " +
+			"A job resource whose job was deleted is removed from the state on its next refresh. Destroying the resource does not delete any job.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name",
+				Optional:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Name of the connection profile the cleanup ran against.",
+			},
+			"retention_days": schema.Int64Attribute{
+				Required:            true,
+				MarkdownDescription: "Number of days a completed job is kept, counted from its end time. Must be greater than 0.",
+			},
+			"plan_only": schema.BoolAttribute{
+				Optional: true,
+				MarkdownDescription: "Whether to only count the jobs older than `retention_days` in `matched_count`, without deleting them, " +
+					"to review a cleanup before running it. Defaults to false.",
+			},
+			"rerun_on": schema.StringAttribute{
+				Optional: true,
+				MarkdownDescription: "Arbitrary value, e.g. a date, that runs the cleanup again when it changes, " +
+					"as the cleanup only runs when the resource is created or updated.",
+			},
+			"matched_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of jobs older than `retention_days` found by the last cleanup.",
+			},
+			"deleted_count": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "Number of jobs deleted by the last cleanup, 0 when `plan_only` is set.",
+			},
+			"last_run": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Time of the last cleanup, in RFC3339 format.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *JobRetentionResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected  Resource Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	r.config.providerConfig = config
+}
+
+// ValidateConfig validates the resource configuration.
+func (r *JobRetentionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var retentionDays types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("retention_days"), &retentionDays)...)
+	if !retentionDays.IsNull() && !retentionDays.IsUnknown() && retentionDays.ValueInt64() <= 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("retention_days"), "invalid retention_days",
+			fmt.Sprintf("retention_days must be greater than 0, got %d.", retentionDays.ValueInt64()))
+	}
+}
+
+// Create runs the cleanup.
+func (r *JobRetentionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *JobRetentionResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !r.cleanup(ctx, &resp.Diagnostics, data) {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// cleanup deletes the jobs that completed more than retention_days ago, or only counts them when plan_only is set.
+// It returns false when an error was reported.
+func (r *JobRetentionResource) cleanup(ctx context.Context, diags *diag.Diagnostics, data *JobRetentionResourceModel) bool {
+	errorHandler := utils.NewErrorHandler(ctx, diags)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return false
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-time.Duration(data.RetentionDays.ValueInt64()) * 24 * time.Hour)
+	jobs, err := interfaces.ListJobsFinishedBefore(errorHandler, *client, cutoff)
+	if err != nil {
+		// error reporting done inside ListJobsFinishedBefore
+		return false
+	}
+
+	deleted := 0
+	if !data.PlanOnly.ValueBool() {
+		for _, job := range jobs {
+			if err = interfaces.DeleteJobByID(errorHandler, *client, strconv.FormatInt(job.ID, 10)); err != nil {
+				// error reporting done inside DeleteJobByID
+				tflog.Warn(ctx, fmt.Sprintf("deleted %d of %d jobs completed before %s", deleted, len(jobs), cutoff.Format(time.RFC3339)))
+				return false
+			}
+			deleted++
+		}
+	}
+	tflog.Info(ctx, fmt.Sprintf("found %d jobs completed before %s, deleted %d", len(jobs), cutoff.Format(time.RFC3339), deleted))
+
+	data.ID = types.StringValue(r.config.profileName(data.CxProfileName))
+	data.MatchedCount = types.Int64Value(int64(len(jobs)))
+	data.DeletedCount = types.Int64Value(int64(deleted))
+	data.LastRun = types.StringValue(now.UTC().Format(time.RFC3339))
+
+	return true
+}
+
+// Read keeps the state as is, the cleanup only runs on apply.
+func (r *JobRetentionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *JobRetentionResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update runs the cleanup again with the new configuration.
+func (r *JobRetentionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *JobRetentionResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !r.cleanup(ctx, &resp.Diagnostics, data) {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Delete removes the resource from the state, no job is deleted.
+func (r *JobRetentionResource) Delete(_ context.Context, _ resource.DeleteRequest, _ *resource.DeleteResponse) {
+}
@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &JobsDataSource{}
+
+// JobsDataSource defines the data source implementation.
+type JobsDataSource struct {
+	config resourceOrDataSourceConfig
+}
+
+// NewJobsDataSource is a helper function to simplify the provider implementation.
+func NewJobsDataSource() datasource.DataSource {
+	return &JobsDataSource{
+		config: resourceOrDataSourceConfig{
+			name:       "jobs_data_source",
+			dataSource: true,
+		},
+	}
+}
+
+// JobsDataSourceModel maps the data source schema data.
+type JobsDataSourceModel struct {
+	CxProfileName types.String             `tfsdk:"cx_profile_name"`
+	Status        types.String             `tfsdk:"status"`
+	FormName      types.String             `tfsdk:"form_name"`
+	Limit         types.Int64              `tfsdk:"limit"`
+	Jobs          []JobsDataSourceJobModel `tfsdk:"jobs"`
+	NumRecords    types.Int64              `tfsdk:"num_records"`
+	Total         types.Int64              `tfsdk:"total"`
+	// SortBy is the key jobs are sorted by, id when null.
+	SortBy types.String `tfsdk:"sort_by"`
+}
+
+// JobsDataSourceJobModel maps a job in the list.
+type JobsDataSourceJobModel struct {
+	ID       types.Int64  `tfsdk:"id"`
+	FormName types.String `tfsdk:"form_name"`
+	Status   types.String `tfsdk:"status"`
+	User     types.String `tfsdk:"user"`
+	Target   types.String `tfsdk:"target"`
+	Counter  types.Int64  `tfsdk:"counter"`
+	Start    types.String `tfsdk:"start"`
+	End      types.String `tfsdk:"end"`
+	Approval types.String `tfsdk:"approval"`
+}
+
+// Metadata returns the data source type name.
+func (d *JobsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.config.name
+}
+
+// Schema defines the schema for the data source.
+func (d *JobsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Jobs data source lists the jobs of Ansible Forms, optionally filtered by status and form. " +
+			"Jobs are sorted by `sort_by`, by id by default, so that their order does not depend on the server.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name. When unset, the provider default_connection_profile is used, else the only connection profile, " +
+					"or the profile named `default` when several are defined.",
+				Optional: true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only list jobs with this status, e.g. success or failed.",
+				Optional:            true,
+			},
+			"form_name": schema.StringAttribute{
+				MarkdownDescription: "Only list jobs of this form.",
+				Optional:            true,
+			},
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of jobs to list. Defaults to no limit.",
+				Optional:            true,
+			},
+			"sort_by": schema.StringAttribute{
+				MarkdownDescription: "Key `jobs` are sorted by, in ascending order: `id`, `start`, `end`, `status`, or `form_name`, jobs with the same key are sorted by id. " +
+					"Jobs without a start or end time come last when sorted by that time. `server` keeps the order sent by the server, which may change between reads. " +
+					"Defaults to `id`, so that the order is deterministic and indexes into `jobs` do not cause spurious diffs.",
+				Optional: true,
+			},
+			"num_records": schema.Int64Attribute{
+				MarkdownDescription: "Number of jobs in `jobs`, across all pages.",
+				Computed:            true,
+			},
+			"total": schema.Int64Attribute{
+				MarkdownDescription: "Total number of jobs reported by the server, in the `total` or `record_count` field of the response, before `limit` and the filters applied by the provider. " +
+					"Null when the server does not report it.",
+				Computed: true,
+			},
+			"jobs": schema.ListNestedAttribute{
+				MarkdownDescription: "Jobs matching the filters, sorted by `sort_by`, empty when there is none.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							MarkdownDescription: "ID of a job.",
+							Computed:            true,
+						},
+						"form_name": schema.StringAttribute{
+							MarkdownDescription: "Form name of a job.",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "Status of a job.",
+							Computed:            true,
+						},
+						"user": schema.StringAttribute{
+							MarkdownDescription: "User who launched a job.",
+							Computed:            true,
+						},
+						"target": schema.StringAttribute{
+							MarkdownDescription: "Target form of a job.",
+							Computed:            true,
+						},
+						"counter": schema.Int64Attribute{
+							MarkdownDescription: "Counter of a job.",
+							Computed:            true,
+						},
+						"start": schema.StringAttribute{
+							MarkdownDescription: "Start time of a job.",
+							Computed:            true,
+						},
+						"end": schema.StringAttribute{
+							MarkdownDescription: "End time of a job.",
+							Computed:            true,
+						},
+						"approval": schema.StringAttribute{
+							MarkdownDescription: "Approval of a job.",
+							Computed:            true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *JobsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Jobs Data Source Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	d.config.providerConfig = config
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *JobsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := d.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data JobsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Limit.ValueInt64() < 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("limit"), "invalid limit", fmt.Sprintf("limit must not be negative, got %d.", data.Limit.ValueInt64()))
+		return
+	}
+	sortBy := data.SortBy.ValueString()
+	if data.SortBy.IsNull() {
+		sortBy = "id"
+	}
+	if !slices.Contains(interfaces.JobSortKeys, sortBy) {
+		resp.Diagnostics.AddAttributeError(path.Root("sort_by"), "invalid sort_by",
+			fmt.Sprintf("sort_by must be one of %s, got %q.", strings.Join(interfaces.JobSortKeys, ", "), sortBy))
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	// we need to defer setting the client until we can read the connection profile name
+	client, err := getRestClient(errorHandler, d.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	filter := interfaces.JobsFilter{
+		Status: data.Status.ValueString(),
+		Form:   data.FormName.ValueString(),
+		Limit:  int(data.Limit.ValueInt64()),
+	}
+	restInfo, total, err := interfaces.ListJobs(errorHandler, *client, filter)
+	if err != nil {
+		// error reporting done inside ListJobs
+		return
+	}
+	interfaces.SortJobs(restInfo, sortBy)
+
+	data.Jobs = make([]JobsDataSourceJobModel, len(restInfo))
+	for index, job := range restInfo {
+		data.Jobs[index] = JobsDataSourceJobModel{
+			ID:       types.Int64Value(job.ID),
+			FormName: types.StringValue(job.Form),
+			Status:   types.StringValue(job.Status),
+			User:     types.StringValue(job.User),
+			Target:   types.StringValue(job.Target),
+			Counter:  types.Int64Value(job.Counter),
+			Start:    types.StringValue(job.Start),
+			End:      types.StringValue(job.End),
+			Approval: types.StringValue(job.Approval),
+		}
+	}
+
+	data.NumRecords = types.Int64Value(int64(len(restInfo)))
+	data.Total = types.Int64Null()
+	if total >= 0 {
+		data.Total = types.Int64Value(total)
+	}
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Debug(ctx, fmt.Sprintf("read a data source: %#v", data))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ function.Function = &JobStatusFunction{}
+)
+
+// NewJobStatusIsTerminalFunction is a helper function to simplify the provider implementation.
+func NewJobStatusIsTerminalFunction() function.Function {
+	return &JobStatusFunction{
+		name:        "jobstatus_is_terminal",
+		summary:     "Check whether a job status is final",
+		description: "Returns true when a job with this status is no longer running: success, warning, or a failed status such as failed, error, aborted, or rejected.",
+		check:       interfaces.IsJobTerminal,
+	}
+}
+
+// NewJobStatusIsSuccessFunction is a helper function to simplify the provider implementation.
+func NewJobStatusIsSuccessFunction() function.Function {
+	return &JobStatusFunction{
+		name:        "jobstatus_is_success",
+		summary:     "Check whether a job status is successful",
+		description: "Returns true when a job with this status completed, possibly with warnings: success or warning.",
+		check:       interfaces.IsJobSuccess,
+	}
+}
+
+// JobStatusFunction classifies a job status, using the same rules as the job resource when waiting for a job.
+type JobStatusFunction struct {
+	name        string
+	summary     string
+	description string
+	check       func(status string) bool
+}
+
+// Metadata returns the function name.
+func (f *JobStatusFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = f.name
+}
+
+// Definition defines the function parameters and return type.
+func (f *JobStatusFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     f.summary,
+		Description: f.description,
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "status",
+				Description: "Status of a job, as reported by the `status` attribute of a job.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+// Run classifies the status.
+func (f *JobStatusFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var status string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &status))
+	if resp.Error != nil {
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, f.check(status)))
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestJobStatusFunctions(t *testing.T) {
+	tests := []struct {
+		status       string
+		wantTerminal bool
+		wantSuccess  bool
+	}{
+		{status: "success", wantTerminal: true, wantSuccess: true},
+		{status: "warning", wantTerminal: true, wantSuccess: true},
+		{status: "failed", wantTerminal: true},
+		{status: "error", wantTerminal: true},
+		{status: "aborted", wantTerminal: true},
+		{status: "canceled", wantTerminal: true},
+		{status: "cancelled", wantTerminal: true},
+		{status: "rejected", wantTerminal: true},
+		{status: "running"},
+		{status: "queued"},
+		{status: "abort"},
+		{status: "approve"},
+		{status: ""},
+	}
+	functions := map[string]function.Function{
+		"jobstatus_is_terminal": NewJobStatusIsTerminalFunction(),
+		"jobstatus_is_success":  NewJobStatusIsSuccessFunction(),
+	}
+	for _, tt := range tests {
+		want := map[string]bool{"jobstatus_is_terminal": tt.wantTerminal, "jobstatus_is_success": tt.wantSuccess}
+		for name, f := range functions {
+			t.Run(name+"_"+tt.status, func(t *testing.T) {
+				req := function.RunRequest{Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(tt.status)})}
+				resp := function.RunResponse{Result: function.NewResultData(types.BoolUnknown())}
+				f.Run(context.Background(), req, &resp)
+				if resp.Error != nil {
+					t.Fatalf("%s(%q) error = %v", name, tt.status, resp.Error)
+				}
+				if got := resp.Result.Value(); !got.Equal(types.BoolValue(want[name])) {
+					t.Errorf("%s(%q) = %v, want %v", name, tt.status, got, want[name])
+				}
+			})
+		}
+	}
+}
+
+func TestJobStatusFunctions_metadata(t *testing.T) {
+	for _, want := range []string{"jobstatus_is_terminal", "jobstatus_is_success"} {
+		found := false
+		for _, newFunction := range (&AnsibleFormsProvider{}).Functions(context.Background()) {
+			var resp function.MetadataResponse
+			newFunction().Metadata(context.Background(), function.MetadataRequest{}, &resp)
+			found = found || resp.Name == want
+		}
+		if !found {
+			t.Errorf("function %s is not registered", want)
+		}
+	}
+}
@@ -16,13 +16,25 @@ type resourceOrDataSourceConfig struct {
 	client         *restclient.RestClient
 	providerConfig Config
 	name           string
+	// dataSource selects the provider data_source_connection_profile when cx_profile_name is not set
+	dataSource bool
+}
+
+// profileName returns the name of the connection profile to use, cxProfileName when set.
+// Otherwise data sources use data_source_connection_profile when set, and an empty name selects the provider default.
+func (config resourceOrDataSourceConfig) profileName(cxProfileName types.String) string {
+	if cxProfileName.ValueString() == "" && config.dataSource {
+		return config.providerConfig.DataSourceConnectionProfile
+	}
+
+	return cxProfileName.ValueString()
 }
 
 // getRestClient will use existing client config.client or create one if it's not set
 func getRestClient(errorHandler *utils.ErrorHandler, config resourceOrDataSourceConfig, cxProfileName types.String) (*restclient.RestClient, error) {
 
 	if config.client == nil {
-		client, err := config.providerConfig.NewClient(errorHandler, cxProfileName.ValueString(), config.name)
+		client, err := config.providerConfig.NewClient(errorHandler, config.profileName(cxProfileName), config.name)
 		if err != nil {
 			return nil, err
 		}
@@ -79,3 +91,19 @@ func jsonStringToMapValue(ctx context.Context, diags *diag.Diagnostics, str stri
 
 	return m
 }
+
+// stringValueOrNull returns value, or current when value is empty and current is null.
+// This avoids a diff when the server returns an empty string for an optional attribute that is not configured.
+func stringValueOrNull(current types.String, value string) types.String {
+	if value == "" && current.IsNull() {
+		return current
+	}
+
+	return types.StringValue(value)
+}
+
+// privateState is implemented by the private state of the requests and responses of a resource.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
@@ -0,0 +1,383 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ScheduleResource{}
+	_ resource.ResourceWithConfigure      = &ScheduleResource{}
+	_ resource.ResourceWithImportState    = &ScheduleResource{}
+	_ resource.ResourceWithValidateConfig = &ScheduleResource{}
+)
+
+// NewScheduleResource is a helper function to simplify the provider implementation.
+func NewScheduleResource() resource.Resource {
+	return &ScheduleResource{
+		config: resourceOrDataSourceConfig{
+			name: "schedule_resource",
+		},
+	}
+}
+
+// ScheduleResource is the resource implementation.
+type ScheduleResource struct {
+	config resourceOrDataSourceConfig
+}
+
+// ScheduleResourceModel maps the resource schema data.
+type ScheduleResourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	ID            types.String `tfsdk:"id"`
+	FormName      types.String `tfsdk:"form_name"`
+	Cron          types.String `tfsdk:"cron"`
+	Extravars     types.Map    `tfsdk:"extravars"`
+	Enabled       types.Bool   `tfsdk:"enabled"`
+}
+
+// Metadata returns the resource type name.
+func (r *ScheduleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.config.name
+}
+
+// Schema defines the schema for the resource.
+func (r *ScheduleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Schedule resource manages a schedule of Ansible Forms, which launches a form on a recurring basis.",
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Connection profile name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "ID of a schedule.",
+			},
+			"form_name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Form name launched by a schedule.",
+			},
+			"cron": schema.StringAttribute{
+				Required: true,
+				MarkdownDescription: "Cron expression of a schedule, with 5 fields (minute hour day-of-month month day-of-week), " +
+					"or 6 fields with leading seconds, e.g. `0 2 * * 1-5`.",
+			},
+			"extravars": schema.MapAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "Extra vars sent as form input values each time the form is launched.",
+			},
+			"enabled": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+				MarkdownDescription: "Whether a schedule launches the form, true by default. Changing it does not recreate the schedule.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ScheduleResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	r.config.providerConfig = config
+}
+
+// ValidateConfig validates the cron expression before it is sent to Ansible Forms.
+func (r *ScheduleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var cron types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("cron"), &cron)...)
+	if resp.Diagnostics.HasError() || cron.IsNull() || cron.IsUnknown() {
+		return
+	}
+	if err := validateCron(cron.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("cron"), "invalid cron expression",
+			fmt.Sprintf("%q is not a valid cron expression: %s.", cron.ValueString(), err))
+	}
+}
+
+// cronField describes the values allowed in a field of a cron expression.
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string // names[i] stands for min+i
+}
+
+var (
+	cronSeconds = cronField{name: "second", min: 0, max: 59}
+	cronFields  = []cronField{
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+		// 0 and 7 are both sunday
+		{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+	}
+)
+
+// validateCron checks a cron expression with 5 fields, or 6 fields when seconds are included.
+func validateCron(expression string) error {
+	values := strings.Fields(expression)
+	fields := cronFields
+	switch len(values) {
+	case 5:
+	case 6:
+		fields = append([]cronField{cronSeconds}, cronFields...)
+	default:
+		return fmt.Errorf("expected 5 or 6 fields, got %d", len(values))
+	}
+	for i, value := range values {
+		if err := fields[i].validate(value); err != nil {
+			return fmt.Errorf("%s field: %s", fields[i].name, err)
+		}
+	}
+
+	return nil
+}
+
+// validate checks a field, a comma separated list of *, values or ranges, each with an optional /step.
+func (f cronField) validate(field string) error {
+	for _, item := range strings.Split(field, ",") {
+		rangeValue, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q", step)
+			}
+		}
+		if rangeValue == "*" {
+			continue
+		}
+		low, high, isRange := strings.Cut(rangeValue, "-")
+		first, err := f.value(low)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		last, err := f.value(high)
+		if err != nil {
+			return err
+		}
+		if first > last {
+			return fmt.Errorf("invalid range %q", rangeValue)
+		}
+	}
+
+	return nil
+}
+
+// value converts a number or a name of a field to its numeric value.
+func (f cronField) value(value string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(value, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, f.min, f.max)
+	}
+
+	return n, nil
+}
+
+// request converts the model to the schedule sent to Ansible Forms.
+func (data *ScheduleResourceModel) request(ctx context.Context, diags *diag.Diagnostics) interfaces.ScheduleResourceModel {
+	return interfaces.ScheduleResourceModel{
+		Form:      data.FormName.ValueString(),
+		Cron:      data.Cron.ValueString(),
+		Extravars: expandExtravars(ctx, diags, data.Extravars),
+		Enabled:   data.Enabled.ValueBool(),
+	}
+}
+
+// Create a new resource.
+func (r *ScheduleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *ScheduleResourceModel
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	request := data.request(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	id, err := interfaces.CreateSchedule(errorHandler, *client, request)
+	if err != nil {
+		// error reporting done inside CreateSchedule
+		return
+	}
+	data.ID = types.StringValue(strconv.FormatInt(id, 10))
+
+	tflog.Trace(ctx, "created a resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ScheduleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *ScheduleResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	schedule, err := interfaces.GetScheduleByID(errorHandler, *client, data.ID.ValueString())
+	if err != nil {
+		return
+	}
+	if schedule == nil {
+		// the schedule was deleted outside of Terraform, e.g. in the UI.
+		tflog.Warn(ctx, fmt.Sprintf("schedule %s no longer exists, removing it from state", data.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	data.FormName = types.StringValue(schedule.Form)
+	data.Cron = types.StringValue(schedule.Cron)
+	data.Enabled = types.BoolValue(schedule.Enabled)
+	extravars, err := schedule.ExtravarsJSON()
+	if err != nil {
+		errorHandler.MakeAndReportError("error reading schedule extravars", fmt.Sprintf("schedule %s: %s", data.ID.ValueString(), err))
+		return
+	}
+	// an empty map and no extravars are the same for Ansible Forms.
+	if extravars != "" && extravars != "{}" {
+		data.Extravars = jsonStringToMapValue(ctx, &resp.Diagnostics, extravars)
+	} else if !data.Extravars.IsNull() && len(data.Extravars.Elements()) != 0 {
+		data.Extravars = types.MapNull(types.StringType)
+	}
+
+	tflog.Debug(ctx, fmt.Sprintf("read a schedule resource: %s", data.ID.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ScheduleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var plan, state *ScheduleResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, plan.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	request := plan.request(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err = interfaces.UpdateSchedule(errorHandler, *client, state.ID.ValueString(), request); err != nil {
+		return
+	}
+	plan.ID = state.ID
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ScheduleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *ScheduleResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+	if err = interfaces.DeleteScheduleByID(errorHandler, *client, data.ID.ValueString()); err != nil {
+		return
+	}
+}
+
+// ImportState imports a schedule, the import ID is <cx_profile_name>,<id>.
+func (r *ScheduleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	cxProfileName, id, found := strings.Cut(req.ID, ",")
+	if !found || cxProfileName == "" || id == "" {
+		resp.Diagnostics.AddError("invalid import ID",
+			fmt.Sprintf("Expected <cx_profile_name>,<id>, got %q.", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cx_profile_name"), cxProfileName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestValidateCron(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    bool
+	}{
+		{expression: "* * * * *"},
+		{expression: "0 2 * * 1-5"},
+		{expression: "*/15 0-6,22-23 1 jan-jun SUN"},
+		{expression: "30 0 2 * * 7"},
+		{expression: "0 0 1 */3 *"},
+		{expression: "* * * *", wantErr: true},
+		{expression: "* * * * * * *", wantErr: true},
+		{expression: "60 * * * *", wantErr: true},
+		{expression: "* 24 * * *", wantErr: true},
+		{expression: "* * 0 * *", wantErr: true},
+		{expression: "* * * 13 *", wantErr: true},
+		{expression: "* * * * 8", wantErr: true},
+		{expression: "5-1 * * * *", wantErr: true},
+		{expression: "*/0 * * * *", wantErr: true},
+		{expression: "@daily", wantErr: true},
+		{expression: "a * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			if err := validateCron(tt.expression); (err != nil) != tt.wantErr {
+				t.Errorf("validateCron() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAccScheduleResource(t *testing.T) {
+	// the id must not change when the schedule is disabled
+	var id string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccScheduleResourceConfig("0 2 * * *", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_schedule_resource.schedule", "cron", "0 2 * * *"),
+					resource.TestCheckResourceAttr("ansible-forms_schedule_resource.schedule", "enabled", "true"),
+					resource.TestCheckResourceAttrSet("ansible-forms_schedule_resource.schedule", "id"),
+					func(s *terraform.State) error {
+						id = s.RootModule().Resources["ansible-forms_schedule_resource.schedule"].Primary.ID
+						return nil
+					}),
+			},
+			// disabling a schedule updates it in place
+			{
+				Config: testAccScheduleResourceConfig("0 2 * * *", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_schedule_resource.schedule", "enabled", "false"),
+					resource.TestCheckResourceAttrPtr("ansible-forms_schedule_resource.schedule", "id", &id)),
+			},
+			{
+				ResourceName: "ansible-forms_schedule_resource.schedule",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return "cluster4," + s.RootModule().Resources["ansible-forms_schedule_resource.schedule"].Primary.ID, nil
+				},
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccScheduleResourceConfig(cron string, enabled bool) string {
+	// environment variables are checked in testAccPreCheck
+	host := os.Getenv("TF_ACC_ANSIBLE_FORMS_HOST")
+	admin := os.Getenv("TF_ACC_ANSIBLE_FORMS_USER")
+	password := os.Getenv("TF_ACC_ANSIBLE_FORMS_PASS")
+	return fmt.Sprintf(`
+provider "ansible-forms" {
+ connection_profiles = [
+    {
+      name = "cluster4"
+      hostname = "%s"
+      username = "%s"
+      password = "%s"
+      validate_certs = false
+    },
+  ]
+}
+
+resource "ansible-forms_schedule_resource" "schedule" {
+  cx_profile_name = "cluster4"
+  form_name       = "Demo Form Ansible No input"
+  cron            = "%s"
+  enabled         = %t
+  extravars = {
+    region = "eu"
+  }
+}`, host, admin, password, cron, enabled)
+}
@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &StatusDataSource{}
+
+// StatusDataSource defines the data source implementation.
+type StatusDataSource struct {
+	config resourceOrDataSourceConfig
+}
+
+// NewStatusDataSource is a helper function to simplify the provider implementation.
+func NewStatusDataSource() datasource.DataSource {
+	return &StatusDataSource{
+		config: resourceOrDataSourceConfig{
+			name:       "status_data_source",
+			dataSource: true,
+		},
+	}
+}
+
+// StatusDataSourceModel maps the data source schema data.
+type StatusDataSourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	Reachable     types.Bool   `tfsdk:"reachable"`
+	Authenticated types.Bool   `tfsdk:"authenticated"`
+	Version       types.String `tfsdk:"version"`
+	// ResponseHeaders holds the headers of the provider response_headers_allowlist
+	ResponseHeaders types.Map `tfsdk:"response_headers"`
+}
+
+// Metadata returns the data source type name.
+func (d *StatusDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + d.config.name
+}
+
+// Schema defines the schema for the data source.
+func (d *StatusDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Status data source checks that Ansible Forms is reachable and accepts the credentials of a connection profile. " +
+			"Reading it fails when the server cannot be reached.",
+
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				MarkdownDescription: "Connection profile name",
+				Required:            true,
+			},
+			"reachable": schema.BoolAttribute{
+				MarkdownDescription: "Whether the server responded.",
+				Computed:            true,
+			},
+			"authenticated": schema.BoolAttribute{
+				MarkdownDescription: "Whether the server accepted the credentials of the connection profile.",
+				Computed:            true,
+			},
+			"version": schema.StringAttribute{
+				MarkdownDescription: "Version of Ansible Forms, empty when the server does not report it.",
+				Computed:            true,
+			},
+			"response_headers": schema.MapAttribute{
+				ElementType: types.StringType,
+				MarkdownDescription: "Headers of the last status request response listed in the provider `response_headers_allowlist`, by canonical name, " +
+					"e.g. `X-Request-Id`. Empty when the allowlist is not set, or when the server or a proxy did not return these headers.",
+				Computed: true,
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *StatusDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Status Data Source Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	d.config.providerConfig = config
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *StatusDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	ctx, done := d.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data StatusDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	// we need to defer setting the client until we can read the connection profile name
+	client, err := getRestClient(errorHandler, d.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	status, err := interfaces.GetServerStatus(errorHandler, *client)
+	if err != nil {
+		// error reporting done inside GetServerStatus
+		return
+	}
+
+	data.Reachable = types.BoolValue(status.Reachable)
+	data.Authenticated = types.BoolValue(status.Authenticated)
+	data.Version = types.StringValue(status.Version)
+	var diags diag.Diagnostics
+	data.ResponseHeaders, diags = types.MapValueFrom(ctx, types.StringType, client.ResponseHeaders())
+	resp.Diagnostics.Append(diags...)
+
+	// Write logs using the tflog package
+	// Documentation: https://terraform.io/plugin/log
+	tflog.Debug(ctx, fmt.Sprintf("read a data source: %#v", data))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// timeoutsBlock returns a timeouts block with a duration attribute for each operation, e.g. create = "30m".
+// It matches the block of the terraform-plugin-framework-timeouts module, see timeoutValue.
+func timeoutsBlock(operations ...string) schema.SingleNestedBlock {
+	attributes := make(map[string]schema.Attribute, len(operations))
+	for _, operation := range operations {
+		attributes[operation] = schema.StringAttribute{
+			MarkdownDescription: fmt.Sprintf("Time allowed for the %s operation, as a duration such as `30s`, `10m`, or `2h`.", operation),
+			Optional:            true,
+		}
+	}
+
+	return schema.SingleNestedBlock{
+		MarkdownDescription: "Timeouts of the operations, each operation is only bounded by the provider `operation_timeout` when its timeout is not set.",
+		Attributes:          attributes,
+	}
+}
+
+// timeoutValue returns the timeout of operation in the timeouts block, 0 when the block or the timeout is not set or unknown.
+// An error is returned when the timeout is not a positive duration.
+func timeoutValue(timeouts types.Object, operation string) (time.Duration, error) {
+	if timeouts.IsNull() || timeouts.IsUnknown() {
+		return 0, nil
+	}
+	value, ok := timeouts.Attributes()[operation].(types.String)
+	if !ok || value.IsNull() || value.IsUnknown() {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(value.ValueString())
+	if err != nil {
+		return 0, fmt.Errorf("expected a duration such as 30m, got %q", value.ValueString())
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("expected a positive duration, got %q", value.ValueString())
+	}
+
+	return timeout, nil
+}
+
+// validateTimeouts reports the timeouts that are not valid durations, or that are shorter than their minimum in minimums.
+func validateTimeouts(diags *diag.Diagnostics, timeouts types.Object, minimums map[string]time.Duration) {
+	if timeouts.IsNull() || timeouts.IsUnknown() {
+		return
+	}
+	for operation := range timeouts.Attributes() {
+		attributePath := path.Root("timeouts").AtName(operation)
+		timeout, err := timeoutValue(timeouts, operation)
+		if err != nil {
+			diags.AddAttributeError(attributePath, "invalid timeouts."+operation, err.Error())
+			continue
+		}
+		if minimum := minimums[operation]; timeout != 0 && timeout < minimum {
+			diags.AddAttributeError(attributePath, "invalid timeouts."+operation,
+				fmt.Sprintf("timeouts.%s must be at least %s, got %s.", operation, minimum, timeout))
+		}
+	}
+}
+
+// withTimeout bounds ctx with the timeout of operation, and reports an error when it is reached, see withOperationDeadline.
+// ctx is returned as is when timeout is 0.
+func withTimeout(ctx context.Context, diags *diag.Diagnostics, operation string, timeout time.Duration) (context.Context, func()) {
+	if timeout == 0 {
+		return ctx, func() {}
+	}
+	deadline := time.Now().Add(timeout)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+
+	return ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !time.Now().Before(deadline) {
+			diags.AddError(fmt.Sprintf("%s timeout exceeded", operation),
+				fmt.Sprintf("The %s timeout of %s was reached at %s, pending requests and job polling were aborted.",
+					operation, timeout, deadline.Format(time.RFC3339)))
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,354 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/interfaces"
+	"terraform-provider-ansible-forms/internal/utils"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &UserResource{}
+	_ resource.ResourceWithConfigure   = &UserResource{}
+	_ resource.ResourceWithImportState = &UserResource{}
+	_ resource.ResourceWithModifyPlan  = &UserResource{}
+)
+
+// NewUserResource is a helper function to simplify the provider implementation.
+func NewUserResource() resource.Resource {
+	return &UserResource{
+		config: resourceOrDataSourceConfig{
+			name: "user_resource",
+		},
+	}
+}
+
+// UserResource is the resource implementation.
+type UserResource struct {
+	config resourceOrDataSourceConfig
+}
+
+// UserResourceModel maps the resource schema data.
+type UserResourceModel struct {
+	CxProfileName types.String `tfsdk:"cx_profile_name"`
+	ID            types.String `tfsdk:"id"`
+	Username      types.String `tfsdk:"username"`
+	Email         types.String `tfsdk:"email"`
+	Role          types.String `tfsdk:"role"`
+	Password      types.String `tfsdk:"password"`
+	PasswordHash  types.String `tfsdk:"password_hash"`
+}
+
+// Metadata returns the resource type name.
+func (r *UserResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.config.name
+}
+
+// Schema defines the schema for the resource.
+func (r *UserResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "User resource manages a local user of Ansible Forms.",
+		Attributes: map[string]schema.Attribute{
+			"cx_profile_name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Connection profile name.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				MarkdownDescription: "ID of a user.",
+			},
+			"username": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Name of a user.",
+			},
+			"email": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Email address of a user.",
+			},
+			"role": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Role of a user.",
+			},
+			"password": schema.StringAttribute{
+				Required:  true,
+				Sensitive: true,
+				MarkdownDescription: "Password of a user. Ansible Forms never returns it, so changes made outside of Terraform are not detected, " +
+					"and it is not set on import. The password is only sent to Ansible Forms when it changes.",
+			},
+			"password_hash": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				MarkdownDescription: "HMAC-SHA256 of the password last set by Terraform, keyed with a random salt kept in the private state of the resource, " +
+					"which changes when the password is rotated. It is computed again with a new salt on the first apply after an import.",
+			},
+		},
+	}
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *UserResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+	config, ok := req.ProviderData.(Config)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected Config, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+	}
+	r.config.providerConfig = config
+}
+
+// passwordSaltKey is the private state key of the salt of password_hash.
+const passwordSaltKey = "password_salt"
+
+// passwordHash returns the hex encoded HMAC-SHA256 of a password keyed with salt, so that the password cannot be looked up from its hash.
+func passwordHash(salt string, password string) string {
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(password))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// getPasswordSalt returns the salt of password_hash saved in private, empty when it is not set, e.g. after an import.
+func getPasswordSalt(ctx context.Context, diags *diag.Diagnostics, private privateState) string {
+	value, d := private.GetKey(ctx, passwordSaltKey)
+	diags.Append(d...)
+	var salt string
+	if len(value) > 0 {
+		if err := json.Unmarshal(value, &salt); err != nil {
+			diags.AddError("invalid private state", fmt.Sprintf("unable to decode %s: %s", passwordSaltKey, err))
+		}
+	}
+
+	return salt
+}
+
+// newPasswordSalt generates a random salt for password_hash, and saves it in private.
+func newPasswordSalt(ctx context.Context, diags *diag.Diagnostics, private privateState) string {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		diags.AddError("unable to generate a salt", err.Error())
+		return ""
+	}
+	salt := hex.EncodeToString(bytes)
+	value, err := json.Marshal(salt)
+	if err != nil {
+		diags.AddError("unable to encode a salt", err.Error())
+		return ""
+	}
+	diags.Append(private.SetKey(ctx, passwordSaltKey, value)...)
+
+	return salt
+}
+
+// request converts the model to the user sent to Ansible Forms, with the password only when withPassword is set.
+func (data *UserResourceModel) request(withPassword bool) interfaces.UserResourceModel {
+	user := interfaces.UserResourceModel{
+		Username: data.Username.ValueString(),
+		Email:    data.Email.ValueString(),
+		Role:     data.Role.ValueString(),
+	}
+	if withPassword {
+		user.Password = data.Password.ValueString()
+	}
+
+	return user
+}
+
+// ModifyPlan sets password_hash from the planned password, so that a rotation shows in the plan.
+// The hash is unknown until applied when there is no salt yet, on create or after an import.
+func (r *UserResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to do on destroy, or on create as the salt is generated by Create
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var password, statePassword, stateHash types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("password"), &password)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("password"), &statePassword)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("password_hash"), &stateHash)...)
+	salt := getPasswordSalt(ctx, &resp.Diagnostics, req.Private)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	hash := types.StringUnknown()
+	switch {
+	case password.IsUnknown() || salt == "":
+	case password.Equal(statePassword):
+		hash = stateHash
+	default:
+		hash = types.StringValue(passwordHash(salt, password.ValueString()))
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("password_hash"), hash)...)
+}
+
+// Create a new resource.
+func (r *UserResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *UserResourceModel
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	id, err := interfaces.CreateUser(errorHandler, *client, data.request(true))
+	if err != nil {
+		// error reporting done inside CreateUser
+		return
+	}
+	data.ID = types.StringValue(strconv.FormatInt(id, 10))
+	salt := newPasswordSalt(ctx, &resp.Diagnostics, resp.Private)
+	data.PasswordHash = types.StringValue(passwordHash(salt, data.Password.ValueString()))
+
+	tflog.Trace(ctx, "created a resource")
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *UserResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *UserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	user, err := interfaces.GetUserByID(errorHandler, *client, data.ID.ValueString())
+	if err != nil {
+		return
+	}
+	if user == nil {
+		tflog.Info(ctx, fmt.Sprintf("user %s no longer exists, removing it from state", data.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// the password is never returned, keep the password and its hash from state.
+	data.Username = types.StringValue(user.Username)
+	data.Email = stringValueOrNull(data.Email, user.Email)
+	data.Role = stringValueOrNull(data.Role, user.Role)
+
+	tflog.Debug(ctx, fmt.Sprintf("read a user resource: %s", data.ID.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *UserResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var plan, state *UserResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, plan.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+
+	// the password is not in state after an import
+	rotated := !plan.Password.Equal(state.Password)
+	if rotated {
+		tflog.Info(ctx, fmt.Sprintf("changing the password of user %s", state.ID.ValueString()))
+	}
+	if err = interfaces.UpdateUser(errorHandler, *client, state.ID.ValueString(), plan.request(rotated)); err != nil {
+		return
+	}
+	plan.ID = state.ID
+	salt := getPasswordSalt(ctx, &resp.Diagnostics, req.Private)
+	if salt == "" {
+		salt = newPasswordSalt(ctx, &resp.Diagnostics, resp.Private)
+	}
+	plan.PasswordHash = types.StringValue(passwordHash(salt, plan.Password.ValueString()))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	ctx, done := r.config.providerConfig.withOperationDeadline(ctx, &resp.Diagnostics)
+	defer done()
+
+	var data *UserResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	errorHandler := utils.NewErrorHandler(ctx, &resp.Diagnostics)
+	client, err := getRestClient(errorHandler, r.config, data.CxProfileName)
+	if err != nil {
+		// error reporting done inside NewClient
+		return
+	}
+	if err = interfaces.DeleteUserByID(errorHandler, *client, data.ID.ValueString()); err != nil {
+		return
+	}
+}
+
+// ImportState imports a user, the import ID is <cx_profile_name>,<id>.
+func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	cxProfileName, id, found := strings.Cut(req.ID, ",")
+	if !found || cxProfileName == "" || id == "" {
+		resp.Diagnostics.AddError("invalid import ID",
+			fmt.Sprintf("Expected <cx_profile_name>,<id>, got %q.", req.ID))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cx_profile_name"), cxProfileName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccUserResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// create
+			{
+				Config: testAccUserResourceConfig("tf_acc_user", "first_password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ansible-forms_user_resource.user", "username", "tf_acc_user"),
+					resource.TestCheckResourceAttr("ansible-forms_user_resource.user", "email", "tf_acc_user@example.com"),
+					resource.TestCheckResourceAttrSet("ansible-forms_user_resource.user", "password_hash"),
+					resource.TestCheckResourceAttrSet("ansible-forms_user_resource.user", "id")),
+			},
+			// password change
+			{
+				Config: testAccUserResourceConfig("tf_acc_user", "second_password"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("ansible-forms_user_resource.user", "password_hash")),
+			},
+			{
+				ResourceName: "ansible-forms_user_resource.user",
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					return "cluster4," + s.RootModule().Resources["ansible-forms_user_resource.user"].Primary.ID, nil
+				},
+				ImportStateVerify: true,
+				// the password is never returned by Ansible Forms
+				ImportStateVerifyIgnore: []string{"password", "password_hash"},
+			},
+			// delete is checked when the test case is destroyed
+		},
+	})
+}
+
+func testAccUserResourceConfig(username string, password string) string {
+	// environment variables are checked in testAccPreCheck
+	host := os.Getenv("TF_ACC_ANSIBLE_FORMS_HOST")
+	admin := os.Getenv("TF_ACC_ANSIBLE_FORMS_USER")
+	adminPassword := os.Getenv("TF_ACC_ANSIBLE_FORMS_PASS")
+	return fmt.Sprintf(`
+provider "ansible-forms" {
+ connection_profiles = [
+    {
+      name = "cluster4"
+      hostname = "%s"
+      username = "%s"
+      password = "%s"
+      validate_certs = false
+    },
+  ]
+}
+
+resource "ansible-forms_user_resource" "user" {
+  cx_profile_name = "cluster4"
+  username        = "%s"
+  email           = "%s@example.com"
+  password        = "%s"
+}`, host, admin, adminPassword, username, username, password)
+}
+
+func TestPasswordHash(t *testing.T) {
+	hash := passwordHash("salt1", "password")
+	if got := passwordHash("salt1", "password"); got != hash {
+		t.Errorf("passwordHash() = %s, want %s with the same salt", got, hash)
+	}
+	if got := passwordHash("salt2", "password"); got == hash {
+		t.Errorf("passwordHash() = %s, want a different hash with another salt", got)
+	}
+	unsalted := sha256.Sum256([]byte("password"))
+	if hash == hex.EncodeToString(unsalted[:]) {
+		t.Errorf("passwordHash() = %s, want a keyed hash", hash)
+	}
+}
@@ -0,0 +1,70 @@
+package restclient
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decompressBody decodes body according to the Content-Encoding header, gzip and deflate are supported.
+// The HTTP transport already decompresses gzip responses to its own requests, this covers proxies that compress regardless.
+func decompressBody(headers http.Header, body []byte) ([]byte, error) {
+	if len(body) == 0 {
+		return body, nil
+	}
+	reader, err := decompressReader(headers, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress %s response: %w", contentEncoding(headers), err)
+	}
+
+	return decompressed, nil
+}
+
+// decompressReader decodes body as it is read, according to the Content-Encoding header, see decompressBody.
+func decompressReader(headers http.Header, body io.Reader) (io.ReadCloser, error) {
+	encoding := contentEncoding(headers)
+	var reader io.ReadCloser
+	var err error
+	switch encoding {
+	case "", "identity":
+		return io.NopCloser(body), nil
+	case "gzip", "x-gzip":
+		reader, err = gzip.NewReader(body)
+	case "deflate":
+		// deflate is zlib wrapped, though some servers send raw deflate
+		buffered := bufio.NewReader(body)
+		if header, _ := buffered.Peek(2); isZlibHeader(header) {
+			reader, err = zlib.NewReader(buffered)
+		} else {
+			reader = flate.NewReader(buffered)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding %q", encoding)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress %s response: %w", encoding, err)
+	}
+
+	return reader, nil
+}
+
+// isZlibHeader reports whether header starts a zlib stream: deflate compression method, and a valid header checksum.
+func isZlibHeader(header []byte) bool {
+	return len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// contentEncoding returns the Content-Encoding of a response, in lower case.
+func contentEncoding(headers http.Header) string {
+	return strings.ToLower(strings.TrimSpace(headers.Get("Content-Encoding")))
+}
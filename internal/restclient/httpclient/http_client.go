@@ -3,45 +3,208 @@ package httpclient
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"golang.org/x/exp/slog"
+	"golang.org/x/net/http/httpproxy"
 )
 
 // HTTPClient represents a client for interaction with an Ansible Forms REST API
 type HTTPClient struct {
-	cxProfile  HTTPProfile
-	ctx        context.Context
-	httpClient http.Client
-	tag        string
+	cxProfile      HTTPProfile
+	ctx            context.Context
+	httpClient     http.Client
+	tag            string
+	userAgent      string
+	requestTimeout time.Duration
+	// session caches the login token, shared by the copies of the client, nil to log in for each request
+	session *session
 }
 
+// session holds the token returned by the last login, see Reauthenticate.
+type session struct {
+	mutex sync.Mutex
+	token string
+}
+
+// defaultRequestTimeout bounds each request when no timeout is set.
+const defaultRequestTimeout = 60 * time.Second
+
 // HTTPProfile defines the connection attributes to build the base URL and authentication header
 type HTTPProfile struct {
 	APIRoot       string
 	Hostname      string
+	Port          int
+	Scheme        string
 	Username      string
 	Password      string
+	Token         string
 	ValidateCerts bool
+	// CACert holds PEM CA certificates, replacing the system CAs when set
+	CACert string
+	// ClientCert and ClientKey hold a PEM certificate and key for mutual TLS, when set
+	ClientCert string
+	ClientKey  string
+	// MinTLSVersion is the minimum TLS version, defaults to TLS 1.2
+	MinTLSVersion uint16
+	// ProxyURL overrides the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables when set
+	ProxyURL string
+	// MaxIdleConns limits the idle connections kept open, defaults to defaultMaxIdleConns
+	MaxIdleConns int
+	// IdleConnTimeout closes idle connections after this delay, defaults to defaultIdleConnTimeout
+	IdleConnTimeout time.Duration
+	// Headers are added to each API request, but not to login requests.  Reserved headers are ignored.
+	Headers map[string]string
+	// DisableRedirects returns redirects as responses rather than following them, e.g. to a login page when a session expired
+	DisableRedirects bool
+	// TLSServerName is used to validate the server certificate, and sent as SNI, rather than Hostname when set
+	TLSServerName string
+	// CompressRequests gzip-compresses the request bodies of at least compressMinBodySize bytes
+	CompressRequests bool
+	// NoProxy lists the hosts reached without a proxy, in the NO_PROXY format, in addition to localhost and loopback addresses
+	NoProxy []string
+}
+
+// reservedHeaders are set by the client, and cannot be replaced with HTTPProfile.Headers.
+var reservedHeaders = []string{"Authorization", "Content-Type", "Content-Encoding", "Content-Length", "Host", "User-Agent", "X-Dot-Client-App"}
+
+// IsReservedHeader reports whether the header name is set by the client, ignoring case.
+func IsReservedHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	for _, reserved := range reservedHeaders {
+		if name == reserved {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Connection pool defaults.  All requests of a profile go to a single host, so all idle connections may be kept for it.
+const (
+	defaultMaxIdleConns    = 100
+	defaultIdleConnTimeout = 90 * time.Second
+)
+
+// transportKey holds the settings of a transport, clients with the same settings share a transport and its connections.
+type transportKey struct {
+	ValidateCerts   bool
+	CACert          string
+	ClientCert      string
+	ClientKey       string
+	MinTLSVersion   uint16
+	ProxyURL        string
+	MaxIdleConns    int
+	IdleConnTimeout time.Duration
+	TLSServerName   string
+	// NoProxy is HTTPProfile.NoProxy joined with commas
+	NoProxy string
 }
 
+var (
+	transportsMutex sync.Mutex
+	transports      = map[transportKey]*http.Transport{}
+)
+
 // NewClient creates a new HTTP client
 func NewClient(ctx context.Context, cxProfile HTTPProfile, tag string) HTTPClient {
 	client := HTTPClient{
 		cxProfile: cxProfile,
 		ctx:       ctx,
 		tag:       tag,
+		session:   &session{},
 	}
 	client.httpClient = client.create()
 
 	return client
 }
 
-// Do sends the API Request, parses the response as JSON, and returns the HTTP status code as int, the "result" value as byte
+// SetUserAgent sets the User-Agent header of each request, including login requests.  An empty value uses the Go default.
+func (c *HTTPClient) SetUserAgent(userAgent string) {
+	c.userAgent = userAgent
+}
+
+// Reauthenticate logs in again with the username and password of the profile, and uses the new token for the next requests,
+// e.g. when the session of the current token expired.  An error is returned when the profile has no username,
+// or when the login is rejected.
+func (c *HTTPClient) Reauthenticate() error {
+	if c.cxProfile.Username == "" {
+		return errors.New("no username and password in the connection profile to log in again")
+	}
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	token, err := (&Request{}).getToken(ctx, c)
+	if err != nil {
+		return fmt.Errorf("login failed: %w", err)
+	}
+	if token == "" {
+		return errors.New("login rejected, check username and password")
+	}
+	if c.session == nil {
+		c.session = &session{}
+	}
+	c.session.setToken(token)
+
+	return nil
+}
+
+// getToken returns the cached login token, empty when none.
+func (s *session) getToken() string {
+	if s == nil {
+		return ""
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.token
+}
+
+// setToken caches the login token, ignored when s is nil.
+func (s *session) setToken(token string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	s.token = token
+	s.mutex.Unlock()
+}
+
+// SetRequestTimeout bounds the time spent on each request, including reading the response.  0 uses the default timeout.
+func (c *HTTPClient) SetRequestTimeout(timeout time.Duration) {
+	c.requestTimeout = timeout
+}
+
+// SetTransport replaces the transport of the client, e.g. with a stub in tests.  Other clients are not affected.
+func (c *HTTPClient) SetTransport(transport http.RoundTripper) {
+	c.httpClient.Transport = transport
+}
+
+// requestContext returns a context bounded by the request timeout, derived from the client context.
+// Canceling the client context aborts the request in flight.
+func (c *HTTPClient) requestContext() (context.Context, context.CancelFunc) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout := c.requestTimeout
+	if timeout == 0 {
+		timeout = defaultRequestTimeout
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Do sends the API Request, parses the response as JSON, and returns the HTTP status code as int, the "result" value as byte,
+// and the response headers (nil when no response was received)
 // possible errors:
 //
 //	no response body:
@@ -49,20 +212,23 @@ func NewClient(ctx context.Context, cxProfile HTTPProfile, tag string) HTTPClien
 //		failed to send HTTP request - statusCode forced to -1 unless it is present in the response
 //		failed to read HTTP response body - statusCode from response if present, otherwise -1
 //		empty response body (check with POST/PATCH/DELETE if this is really a problem)  - statusCode from response if present, otherwise -1
-func (c *HTTPClient) Do(baseURL string, req *Request) (int, []byte, error) {
-	httpReq, err := req.BuildHTTPReq(c, baseURL)
+func (c *HTTPClient) Do(baseURL string, req *Request) (int, []byte, http.Header, error) {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	httpReq, err := req.BuildHTTPReq(ctx, c, baseURL)
 	statusCode := -1
 	if err != nil {
-		return statusCode, nil, err
+		return statusCode, nil, nil, err
 	}
-	tflog.Debug(c.ctx, fmt.Sprintf("sending: %s %s", httpReq.Method, httpReq.URL.String()), map[string]any{"body": req.Body})
+	// the body is logged by RestClient, after redacting sensitive values
+	tflog.Debug(c.ctx, fmt.Sprintf("sending: %s %s", httpReq.Method, httpReq.URL.String()))
 	httpRes, err := c.httpClient.Do(httpReq)
 	if httpRes != nil {
 		statusCode = httpRes.StatusCode
 	}
 	if err != nil {
 		tflog.Error(c.ctx, fmt.Sprintf("HTTP request failed: %s, statusCode: %d, err raw:%#v", err, statusCode, err))
-		return statusCode, nil, err
+		return statusCode, nil, nil, err
 	}
 
 	defer func(Body io.ReadCloser) {
@@ -75,23 +241,205 @@ func (c *HTTPClient) Do(baseURL string, req *Request) (int, []byte, error) {
 	body, err := io.ReadAll(httpRes.Body)
 	if err != nil {
 		tflog.Error(c.ctx, fmt.Sprintf("HTTP response read failed: %s, statusCode: %d", err, statusCode))
-		return statusCode, nil, err
+		return statusCode, nil, httpRes.Header, err
 	}
 
 	if body == nil {
-		return httpRes.StatusCode, nil, fmt.Errorf("no result returned in REST response.  statusCode %d", statusCode)
+		return httpRes.StatusCode, nil, httpRes.Header, fmt.Errorf("no result returned in REST response.  statusCode %d", statusCode)
+	}
+
+	tflog.Debug(c.ctx, fmt.Sprintf("received: %s %s %d, %d bytes", req.Method, httpReq.URL.String(), statusCode, len(body)))
+
+	return httpRes.StatusCode, body, httpRes.Header, nil
+}
+
+// maxStreamErrorBodySize limits the body read from a failed streamed request, which is only used to report the error.
+const maxStreamErrorBodySize = 1 << 20
+
+// DoStream sends the API Request as Do, and passes the response body to consume as it is received rather than reading it in memory,
+// e.g. for a large job output.  consume is only called for a 2xx response, its error is returned as is.
+// For other responses, the start of the body is returned so that the caller can report the error, as with Do.
+// The whole transfer, including consume, is bounded by the request timeout.
+func (c *HTTPClient) DoStream(baseURL string, req *Request, consume func(headers http.Header, body io.Reader) error) (int, []byte, http.Header, error) {
+	ctx, cancel := c.requestContext()
+	defer cancel()
+	httpReq, err := req.BuildHTTPReq(ctx, c, baseURL)
+	statusCode := -1
+	if err != nil {
+		return statusCode, nil, nil, err
+	}
+	tflog.Debug(c.ctx, fmt.Sprintf("sending: %s %s, streaming the response", httpReq.Method, httpReq.URL.String()))
+	httpRes, err := c.httpClient.Do(httpReq)
+	if httpRes != nil {
+		statusCode = httpRes.StatusCode
+	}
+	if err != nil {
+		tflog.Error(c.ctx, fmt.Sprintf("HTTP request failed: %s, statusCode: %d, err raw:%#v", err, statusCode, err))
+		return statusCode, nil, nil, err
+	}
+
+	defer func(Body io.ReadCloser) {
+		err = Body.Close()
+		if err != nil {
+			slog.Error("error closing body", err)
+		}
+	}(httpRes.Body)
+
+	if statusCode < 200 || statusCode > 299 {
+		body, err := io.ReadAll(io.LimitReader(httpRes.Body, maxStreamErrorBodySize))
+		if err != nil {
+			tflog.Error(c.ctx, fmt.Sprintf("HTTP response read failed: %s, statusCode: %d", err, statusCode))
+			return statusCode, nil, httpRes.Header, err
+		}
+		return statusCode, body, httpRes.Header, nil
 	}
 
-	tflog.Debug(c.ctx, fmt.Sprintf("received: %s %s %d", req.Method, httpReq.URL.String(), statusCode), map[string]any{"res": string(body)})
+	counter := &countingReader{reader: httpRes.Body}
+	if err := consume(httpRes.Header, counter); err != nil {
+		tflog.Error(c.ctx, fmt.Sprintf("HTTP response stream failed: %s, statusCode: %d, after %d bytes", err, statusCode, counter.count))
+		return statusCode, nil, httpRes.Header, err
+	}
+	tflog.Debug(c.ctx, fmt.Sprintf("received: %s %s %d, %d bytes streamed", req.Method, httpReq.URL.String(), statusCode, counter.count))
 
-	return httpRes.StatusCode, body, nil
+	return statusCode, nil, httpRes.Header, nil
+}
+
+// countingReader counts the bytes read from reader, to log the size of a streamed response.
+type countingReader struct {
+	reader io.Reader
+	count  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.count += int64(n)
+
+	return n, err
 }
 
 // create configures and creates the http client
 func (c *HTTPClient) create() http.Client {
-	if !c.cxProfile.ValidateCerts {
-		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	// requests are bounded by requestTimeout, see requestContext
+	if c.cxProfile.DisableRedirects {
+		return http.Client{Transport: c.transport(), CheckRedirect: noRedirect}
+	}
+	return http.Client{Transport: c.transport(), CheckRedirect: checkRedirect}
+}
+
+// transport returns the transport shared by the clients with the same settings, so that connections are reused across clients.
+func (c *HTTPClient) transport() *http.Transport {
+	key := transportKey{
+		ValidateCerts:   c.cxProfile.ValidateCerts,
+		CACert:          c.cxProfile.CACert,
+		ClientCert:      c.cxProfile.ClientCert,
+		ClientKey:       c.cxProfile.ClientKey,
+		MinTLSVersion:   c.cxProfile.MinTLSVersion,
+		ProxyURL:        c.cxProfile.ProxyURL,
+		MaxIdleConns:    c.cxProfile.MaxIdleConns,
+		IdleConnTimeout: c.cxProfile.IdleConnTimeout,
+		TLSServerName:   c.cxProfile.TLSServerName,
+		NoProxy:         strings.Join(c.cxProfile.NoProxy, ","),
+	}
+	transportsMutex.Lock()
+	defer transportsMutex.Unlock()
+	if transport, ok := transports[key]; ok {
+		return transport
+	}
+	transport := c.newTransport()
+	transports[key] = transport
+
+	return transport
+}
+
+// proxy returns the proxy of each request: ProxyURL when set, otherwise the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables.
+// The hosts in NoProxy, and localhost and loopback addresses, are reached directly.
+func (c *HTTPClient) proxy() func(*http.Request) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	if c.cxProfile.ProxyURL != "" {
+		if _, err := url.Parse(c.cxProfile.ProxyURL); err != nil {
+			// do not log the URL, it may contain credentials
+			tflog.Error(c.ctx, "unable to parse proxy URL")
+		} else {
+			// the environment does not apply, including NO_PROXY
+			config = &httpproxy.Config{HTTPProxy: c.cxProfile.ProxyURL, HTTPSProxy: c.cxProfile.ProxyURL}
+		}
+	}
+	noProxy := c.cxProfile.NoProxy
+	if config.NoProxy != "" {
+		noProxy = append([]string{config.NoProxy}, noProxy...)
+	}
+	config.NoProxy = strings.Join(noProxy, ",")
+	proxyFunc := config.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+// newTransport creates a transport with the TLS, proxy, and connection pool settings of the profile.
+func (c *HTTPClient) newTransport() *http.Transport {
+	minTLSVersion := c.cxProfile.MinTLSVersion
+	if minTLSVersion == 0 {
+		minTLSVersion = tls.VersionTLS12
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: !c.cxProfile.ValidateCerts, MinVersion: minTLSVersion, ServerName: c.cxProfile.TLSServerName}
+	if c.cxProfile.CACert != "" {
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM([]byte(c.cxProfile.CACert)) {
+			tflog.Error(c.ctx, "no valid PEM certificate found in CA certificate")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	if c.cxProfile.ClientCert != "" {
+		certificate, err := tls.X509KeyPair([]byte(c.cxProfile.ClientCert), []byte(c.cxProfile.ClientKey))
+		if err != nil {
+			tflog.Error(c.ctx, fmt.Sprintf("unable to load client certificate: %s", err))
+		} else {
+			tlsConfig.Certificates = []tls.Certificate{certificate}
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = c.proxy()
+	maxIdleConns := c.cxProfile.MaxIdleConns
+	if maxIdleConns == 0 {
+		maxIdleConns = defaultMaxIdleConns
+	}
+	idleConnTimeout := c.cxProfile.IdleConnTimeout
+	if idleConnTimeout == 0 {
+		idleConnTimeout = defaultIdleConnTimeout
 	}
+	transport.MaxIdleConns = maxIdleConns
+	// the default of 2 idle connections per host closes most connections when requests run in parallel
+	transport.MaxIdleConnsPerHost = maxIdleConns
+	transport.IdleConnTimeout = idleConnTimeout
+	// HTTP/2 is otherwise disabled by a custom TLS configuration
+	transport.ForceAttemptHTTP2 = true
+
+	return transport
+}
+
+// maxRedirects is the number of redirects followed for a request, as for the default http.Client.
+const maxRedirects = 10
+
+// checkRedirect follows a redirect when it keeps the method, so that a POST is not silently turned into a GET.
+// The Authorization header is only sent to the host of the original request.
+// Redirects that are not followed are reported by RestClient, with their Location.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if req.Method != via[0].Method {
+		return http.ErrUseLastResponse
+	}
+	if req.URL.Host != via[0].URL.Host {
+		req.Header.Del("Authorization")
+	}
+
+	return nil
+}
 
-	return http.Client{Timeout: 120 * time.Second}
+// noRedirect never follows a redirect, which is then reported by RestClient with its Location.
+func noRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
 }
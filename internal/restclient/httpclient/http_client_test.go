@@ -2,10 +2,21 @@ package httpclient
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestHTTPClient_Do(t *testing.T) {
@@ -78,7 +89,7 @@ func TestHTTPClient_Do(t *testing.T) {
 				ctx:        tt.fields.ctx,
 				httpClient: tt.fields.httpClient,
 			}
-			got, got1, err := c.Do(tt.args.baseURL, tt.args.req)
+			got, got1, _, err := c.Do(tt.args.baseURL, tt.args.req)
 			if err != nil {
 				fmt.Printf("err: %s\n", err)
 			}
@@ -95,3 +106,406 @@ func TestHTTPClient_Do(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPClient_Do_token(t *testing.T) {
+	var gotAuthorization string
+	loginCalled := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/auth/login") {
+			loginCalled = true
+		}
+		gotAuthorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer server.Close()
+
+	c := &HTTPClient{
+		cxProfile: HTTPProfile{
+			APIRoot:  "api",
+			Hostname: strings.TrimPrefix(server.URL, "https://"),
+			Token:    "my-token",
+		},
+		ctx:        context.Background(),
+		httpClient: *server.Client(),
+	}
+	statusCode, _, _, err := c.Do("job", &Request{Method: "GET"})
+	if err != nil {
+		t.Fatalf("HTTPClient.Do() error = %v", err)
+	}
+	if statusCode != 200 {
+		t.Errorf("HTTPClient.Do() got = %v, want 200", statusCode)
+	}
+	if gotAuthorization != "Bearer my-token" {
+		t.Errorf("HTTPClient.Do() Authorization = %q, want %q", gotAuthorization, "Bearer my-token")
+	}
+	if loginCalled {
+		t.Errorf("HTTPClient.Do() unexpected call to auth/login with a token")
+	}
+}
+
+func TestHTTPClient_Do_redirectAuthorization(t *testing.T) {
+	var otherHostAuthorization, sameHostAuthorization string
+	otherHost := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherHostAuthorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer otherHost.Close()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/moved":
+			http.Redirect(w, r, "/api/job", http.StatusTemporaryRedirect)
+		case "/api/other":
+			http.Redirect(w, r, otherHost.URL+"/api/job", http.StatusTemporaryRedirect)
+		default:
+			sameHostAuthorization = r.Header.Get("Authorization")
+			_, _ = w.Write([]byte(`{"status":"success"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(context.Background(), HTTPProfile{APIRoot: "api", Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "my-token"}, "test")
+	if _, _, _, err := c.Do("moved", &Request{Method: "GET"}); err != nil {
+		t.Fatalf("HTTPClient.Do() error = %v", err)
+	}
+	if sameHostAuthorization != "Bearer my-token" {
+		t.Errorf("same host redirect Authorization = %q, want %q", sameHostAuthorization, "Bearer my-token")
+	}
+	if _, _, _, err := c.Do("other", &Request{Method: "GET"}); err != nil {
+		t.Fatalf("HTTPClient.Do() error = %v", err)
+	}
+	if otherHostAuthorization != "" {
+		t.Errorf("other host redirect Authorization = %q, want none", otherHostAuthorization)
+	}
+}
+
+func TestHTTPClient_Do_redirectMethodChange(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("redirect followed with %s", r.Method)
+		}
+		http.Redirect(w, r, "/api/job", http.StatusFound)
+	}))
+	defer server.Close()
+
+	c := NewClient(context.Background(), HTTPProfile{APIRoot: "api", Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "my-token"}, "test")
+	statusCode, _, headers, err := c.Do("moved", &Request{Method: "POST", Body: map[string]any{"form": "demo"}})
+	if err != nil {
+		t.Fatalf("HTTPClient.Do() error = %v", err)
+	}
+	if statusCode != http.StatusFound || headers.Get("Location") != "/api/job" {
+		t.Errorf("HTTPClient.Do() = %d, Location %q, want %d, %q", statusCode, headers.Get("Location"), http.StatusFound, "/api/job")
+	}
+}
+
+func TestHTTPClient_create_caCert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer server.Close()
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := []struct {
+		name    string
+		caCert  string
+		wantErr bool
+	}{
+		{name: "system_cas", caCert: "", wantErr: true},
+		{name: "ca_cert", caCert: caCert, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(context.Background(), HTTPProfile{
+				APIRoot:       "api",
+				Hostname:      strings.TrimPrefix(server.URL, "https://"),
+				Token:         "my-token",
+				ValidateCerts: true,
+				CACert:        tt.caCert,
+			}, "test")
+			_, _, _, err := c.Do("job", &Request{Method: "GET"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HTTPClient.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newClientCertificate returns a self-signed client certificate and its key, PEM encoded.
+func newClientCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "terraform"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestHTTPClient_create_clientCert(t *testing.T) {
+	clientCert, clientKey := newClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM([]byte(clientCert))
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		clientCert string
+		clientKey  string
+		wantErr    bool
+	}{
+		{name: "no_client_cert", wantErr: true},
+		{name: "client_cert", clientCert: clientCert, clientKey: clientKey, wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(context.Background(), HTTPProfile{
+				APIRoot:    "api",
+				Hostname:   strings.TrimPrefix(server.URL, "https://"),
+				Token:      "my-token",
+				ClientCert: tt.clientCert,
+				ClientKey:  tt.clientKey,
+			}, "test")
+			_, _, _, err := c.Do("job", &Request{Method: "GET"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HTTPClient.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// newServerCertificate returns a self-signed server certificate for dnsName only, and the certificate PEM encoded.
+func newServerCertificate(t *testing.T, dnsName string) (tls.Certificate, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: dnsName},
+		DNSNames:              []string{dnsName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestHTTPClient_create_tlsServerName(t *testing.T) {
+	// the certificate does not match the 127.0.0.1 address the client dials
+	certificate, caCert := newServerCertificate(t, "forms.example.com")
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{certificate}}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		tlsServerName string
+		wantErr       bool
+	}{
+		{name: "hostname", wantErr: true},
+		{name: "tls_server_name", tlsServerName: "forms.example.com", wantErr: false},
+		{name: "other_name", tlsServerName: "other.example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(context.Background(), HTTPProfile{
+				APIRoot:       "api",
+				Hostname:      strings.TrimPrefix(server.URL, "https://"),
+				Token:         "my-token",
+				ValidateCerts: true,
+				CACert:        caCert,
+				TLSServerName: tt.tlsServerName,
+			}, "test")
+			_, _, _, err := c.Do("job", &Request{Method: "GET"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HTTPClient.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_create_minTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		minTLSVersion uint16
+		wantErr       bool
+	}{
+		{name: "default", minTLSVersion: 0, wantErr: false},
+		{name: "tls_1.2", minTLSVersion: tls.VersionTLS12, wantErr: false},
+		{name: "tls_1.3", minTLSVersion: tls.VersionTLS13, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(context.Background(), HTTPProfile{
+				APIRoot:       "api",
+				Hostname:      strings.TrimPrefix(server.URL, "https://"),
+				Token:         "my-token",
+				MinTLSVersion: tt.minTLSVersion,
+			}, "test")
+			_, _, _, err := c.Do("job", &Request{Method: "GET"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HTTPClient.Do() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_create_proxyURL(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a proxy receives the absolute URL of the target
+		proxied = append(proxied, r.URL.String())
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer proxy.Close()
+
+	c := NewClient(context.Background(), HTTPProfile{
+		APIRoot:  "api",
+		Hostname: "ansible-forms.invalid",
+		Scheme:   "http",
+		Token:    "my-token",
+		ProxyURL: proxy.URL,
+	}, "test")
+	statusCode, _, _, err := c.Do("job", &Request{Method: "GET"})
+	if err != nil {
+		t.Fatalf("HTTPClient.Do() error = %v", err)
+	}
+	if statusCode != 200 {
+		t.Errorf("HTTPClient.Do() got = %v, want 200", statusCode)
+	}
+	if !reflect.DeepEqual(proxied, []string{"http://ansible-forms.invalid/api/job"}) {
+		t.Errorf("HTTPClient.Do() proxied requests = %v", proxied)
+	}
+}
+
+func TestHTTPClient_proxy_noProxy(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   HTTPProfile
+		url       string
+		wantProxy string
+	}{
+		{name: "proxied", profile: HTTPProfile{ProxyURL: "http://proxy.invalid:3128"}, url: "https://forms.example.com/api", wantProxy: "http://proxy.invalid:3128"},
+		{name: "no_proxy host", profile: HTTPProfile{ProxyURL: "http://proxy.invalid:3128", NoProxy: []string{"forms.example.com"}}, url: "https://forms.example.com/api"},
+		{name: "no_proxy domain", profile: HTTPProfile{ProxyURL: "http://proxy.invalid:3128", NoProxy: []string{"other.test", ".example.com"}}, url: "https://forms.example.com:8443/api"},
+		{name: "no_proxy CIDR", profile: HTTPProfile{ProxyURL: "http://proxy.invalid:3128", NoProxy: []string{"10.0.0.0/8"}}, url: "https://10.1.2.3/api"},
+		{name: "localhost", profile: HTTPProfile{ProxyURL: "http://proxy.invalid:3128"}, url: "https://localhost:8443/api"},
+		{name: "loopback", profile: HTTPProfile{ProxyURL: "http://proxy.invalid:3128"}, url: "https://127.0.0.1:8443/api"},
+		{name: "environment", url: "https://forms.example.com/api", wantProxy: "http://env-proxy.invalid:3128"},
+		{name: "environment and no_proxy", profile: HTTPProfile{NoProxy: []string{"forms.example.com"}}, url: "https://forms.example.com/api"},
+		{name: "environment NO_PROXY", url: "https://forms.env.test/api"},
+	}
+	t.Setenv("HTTPS_PROXY", "http://env-proxy.invalid:3128")
+	t.Setenv("NO_PROXY", ".env.test")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &HTTPClient{cxProfile: tt.profile, ctx: context.Background()}
+			req, err := http.NewRequest("GET", tt.url, nil)
+			if err != nil {
+				t.Fatalf("http.NewRequest() error = %v", err)
+			}
+			got, err := c.proxy()(req)
+			if err != nil {
+				t.Fatalf("proxy() error = %v", err)
+			}
+			gotProxy := ""
+			if got != nil {
+				gotProxy = got.String()
+			}
+			if gotProxy != tt.wantProxy {
+				t.Errorf("proxy() = %q, want %q", gotProxy, tt.wantProxy)
+			}
+		})
+	}
+}
+
+func TestHTTPClient_create_noProxy(t *testing.T) {
+	// an unreachable proxy, requests sent through it fail
+	t.Setenv("HTTPS_PROXY", "http://127.0.0.1:1")
+	t.Setenv("NO_PROXY", "")
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"success"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(context.Background(), HTTPProfile{
+		APIRoot:  "api",
+		Hostname: strings.TrimPrefix(server.URL, "https://"),
+		Token:    "my-token",
+		// a distinct setting, so that the transport is not shared with other tests
+		NoProxy: []string{"127.0.0.1", "forms.internal.test"},
+	}, "test")
+	statusCode, _, _, err := c.Do("job", &Request{Method: "GET"})
+	if err != nil {
+		t.Fatalf("HTTPClient.Do() error = %v, want the request sent directly", err)
+	}
+	if statusCode != 200 {
+		t.Errorf("HTTPClient.Do() got = %v, want 200", statusCode)
+	}
+}
+
+func TestHTTPClient_Do_requestTimeout(t *testing.T) {
+	aborted := make(chan struct{}, 1)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// hang until the client aborts the request
+		<-r.Context().Done()
+		aborted <- struct{}{}
+	}))
+	defer server.Close()
+
+	c := NewClient(context.Background(), HTTPProfile{
+		APIRoot:  "api",
+		Hostname: strings.TrimPrefix(server.URL, "https://"),
+		Token:    "my-token",
+	}, "test")
+	c.SetRequestTimeout(50 * time.Millisecond)
+	start := time.Now()
+	if _, _, _, err := c.Do("job", &Request{Method: "GET"}); err == nil {
+		t.Fatalf("HTTPClient.Do() expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("HTTPClient.Do() returned after %s", elapsed)
+	}
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Errorf("HTTPClient.Do() did not abort the request")
+	}
+}
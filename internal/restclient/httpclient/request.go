@@ -2,11 +2,15 @@ package httpclient
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"golang.org/x/exp/slog"
 )
@@ -17,41 +21,95 @@ type Request struct {
 	Body   map[string]any `json:"body"`
 	Query  url.Values     `json:"query"`
 	// uuid   string
+	// Headers are added to this request after the profile headers.  Reserved headers are ignored.
+	Headers map[string]string `json:"headers"`
+}
+
+// compressMinBodySize is the size of the smallest request body compressed when CompressRequests is set,
+// smaller bodies are sent as is, as compressing them saves little.
+const compressMinBodySize = 4096
+
+// gzipBody compresses a request body.
+func gzipBody(body []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
 }
 
 // BuildHTTPReq builds an HTTP request to carry out the REST request
-func (r *Request) BuildHTTPReq(c *HTTPClient, baseURL string) (*http.Request, error) {
+// The request, and the login request when needed, are aborted when ctx is done.
+func (r *Request) BuildHTTPReq(ctx context.Context, c *HTTPClient, baseURL string) (*http.Request, error) {
 	_url, err := r.BuildURL(c, baseURL, "")
 	if err != nil {
 		return nil, err
 	}
 	var req *http.Request
 	var body io.Reader
+	compressed := false
 	if len(r.Body) != 0 {
 		var bodyJSON []byte
 		bodyJSON, err = json.Marshal(r.Body)
 		if err != nil {
 			return nil, err
 		}
+		if c.cxProfile.CompressRequests && len(bodyJSON) >= compressMinBodySize {
+			bodyJSON, err = gzipBody(bodyJSON)
+			if err != nil {
+				return nil, err
+			}
+			compressed = true
+		}
 		body = bytes.NewReader(bodyJSON)
 	}
-	req, err = http.NewRequest(r.Method, _url, body)
+	req, err = http.NewRequestWithContext(ctx, r.Method, _url, body)
 
 	if err != nil {
 		return nil, err
 	}
 
+	for name, value := range c.cxProfile.Headers {
+		if !IsReservedHeader(name) {
+			req.Header.Set(name, value)
+		}
+	}
+	for name, value := range r.Headers {
+		if !IsReservedHeader(name) {
+			req.Header.Set(name, value)
+		}
+	}
 	req.Header.Set("Content-Type", "application/json")
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
 	//req.SetBasicAuth(c.cxProfile.Username, c.cxProfile.Password)
 
-	token, err := r.getToken(c)
-	if err != nil {
-		return nil, err
+	// the token of the last login is used when set, e.g. after Reauthenticate, then an API token is used as is,
+	// otherwise log in with username and password, and keep the token for the next requests.
+	token := c.session.getToken()
+	if token == "" {
+		token = c.cxProfile.Token
+	}
+	if token == "" {
+		token, err = r.getToken(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		c.session.setToken(token)
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	// telemetry header
+	// telemetry headers
 	req.Header.Set("X-Dot-Client-App", c.tag)
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 	// TODO: low pty: add support for form data (require to create a file)
 
 	return req, err
@@ -70,9 +128,17 @@ func (r *Request) BuildURL(c *HTTPClient, baseURL string, uuid string) (string,
 	if err != nil {
 		return "", err
 	}
+	scheme := c.cxProfile.Scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	host := c.cxProfile.Hostname
+	if c.cxProfile.Port != 0 {
+		host = net.JoinHostPort(host, strconv.Itoa(c.cxProfile.Port))
+	}
 	u := &url.URL{
-		Scheme: "https",
-		Host:   c.cxProfile.Hostname,
+		Scheme: scheme,
+		Host:   host,
 		Path:   c.cxProfile.APIRoot,
 	}
 	u = u.JoinPath(baseURL, uuid)
@@ -88,22 +154,25 @@ type authResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
-func (r *Request) getToken(c *HTTPClient) (string, error) {
+func (r *Request) getToken(ctx context.Context, c *HTTPClient) (string, error) {
 	_url, err := r.BuildURL(c, "auth/login", "")
 	if err != nil {
 		return "", err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, _url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, _url, nil)
 	if err != nil {
 		return "", err
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
 	req.SetBasicAuth(c.cxProfile.Username, c.cxProfile.Password)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// use the same transport, for TLS and proxy settings
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return "", err
 	}
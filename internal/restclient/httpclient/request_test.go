@@ -1,8 +1,13 @@
 package httpclient
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"io"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -14,6 +19,9 @@ func TestRequest_BuildURL(t *testing.T) {
 	client := &HTTPClient{
 		cxProfile: cxProfile,
 	}
+	clientWithPort := &HTTPClient{
+		cxProfile: HTTPProfile{Hostname: "host", APIRoot: "api", Scheme: "http", Port: 8080},
+	}
 	clientEmptyValues := &HTTPClient{
 		cxProfile: HTTPProfile{},
 	}
@@ -39,6 +47,7 @@ func TestRequest_BuildURL(t *testing.T) {
 		{name: "test1", fields: fields{Method: "GET", Body: nil, Query: nil}, args: args{c: client, baseURL: "cluster"}, want: "https://host/api/cluster", wantErr: false},
 		{name: "test2", fields: fields{Method: "GET", Body: nil, Query: query}, args: args{c: client, baseURL: "cluster", uuid: "123"}, want: "https://host/api/cluster/123?fields=f1%2Cf2", wantErr: false},
 		{name: "test3", fields: fields{Method: "GET", Body: nil, Query: query}, args: args{c: nil, baseURL: "cluster", uuid: "123"}, want: "", wantErr: true},
+		{name: "test_scheme_port", fields: fields{Method: "GET", Body: nil, Query: nil}, args: args{c: clientWithPort, baseURL: "cluster"}, want: "http://host:8080/api/cluster", wantErr: false},
 		{name: "test4", fields: fields{Method: "GET", Body: nil, Query: query}, args: args{c: clientEmptyValues, baseURL: "cluster", uuid: "123"}, want: "", wantErr: true},
 	}
 	for _, tt := range tests {
@@ -110,7 +119,7 @@ func TestRequest_BuildHTTPReq(t *testing.T) {
 				Body:   tt.fields.Body,
 				Query:  tt.fields.Query,
 			}
-			got, err := r.BuildHTTPReq(tt.args.c, tt.args.baseURL)
+			got, err := r.BuildHTTPReq(context.Background(), tt.args.c, tt.args.baseURL)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Request.BuildHTTPReq() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -133,3 +142,48 @@ func TestRequest_BuildHTTPReq(t *testing.T) {
 		})
 	}
 }
+
+func TestRequest_BuildHTTPReq_compressRequests(t *testing.T) {
+	large := map[string]any{"extravars": strings.Repeat("data", compressMinBodySize)}
+	small := map[string]any{"extravars": "data"}
+	tests := []struct {
+		name             string
+		compressRequests bool
+		body             map[string]any
+		wantEncoding     string
+	}{
+		{name: "large", compressRequests: true, body: large, wantEncoding: "gzip"},
+		{name: "small", compressRequests: true, body: small, wantEncoding: ""},
+		{name: "disabled", compressRequests: false, body: large, wantEncoding: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &HTTPClient{
+				cxProfile: HTTPProfile{Hostname: "host", APIRoot: "api", Token: "token", CompressRequests: tt.compressRequests},
+				ctx:       context.TODO(),
+			}
+			// Content-Encoding is set by the client, not by request headers
+			r := &Request{Method: "POST", Body: tt.body, Headers: map[string]string{"Content-Encoding": "br"}}
+			got, err := r.BuildHTTPReq(context.Background(), client, "job")
+			if err != nil {
+				t.Fatalf("Request.BuildHTTPReq() error = %v", err)
+			}
+			if encoding := got.Header.Get("Content-Encoding"); encoding != tt.wantEncoding {
+				t.Errorf("Request.BuildHTTPReq() Content-Encoding = %q, want %q", encoding, tt.wantEncoding)
+			}
+			var reader io.Reader = got.Body
+			if tt.wantEncoding == "gzip" {
+				if reader, err = gzip.NewReader(got.Body); err != nil {
+					t.Fatalf("gzip.NewReader() error = %v", err)
+				}
+			}
+			var decoded map[string]any
+			if err = json.NewDecoder(reader).Decode(&decoded); err != nil {
+				t.Fatalf("decoding the body error = %v", err)
+			}
+			if !reflect.DeepEqual(decoded, tt.body) {
+				t.Errorf("Request.BuildHTTPReq() body = %.40v, want %.40v", decoded, tt.body)
+			}
+		})
+	}
+}
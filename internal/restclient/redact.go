@@ -0,0 +1,119 @@
+package restclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SensitiveKeys lists the keys whose values are masked before a request or response is logged.
+// A key matches when it contains one of these strings, ignoring case.
+// Resources and data sources may append their own keys.
+var SensitiveKeys = []string{"password", "token", "secret", "api_key"}
+
+// isSensitiveKey reports whether the value of key must be masked.
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitiveKey := range SensitiveKeys {
+		if strings.Contains(key, strings.ToLower(sensitiveKey)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact returns a copy of value with the values of sensitive keys masked, in nested maps and lists.
+// Other values are returned as is.
+func Redact(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		return redactMap(typed)
+	case []map[string]any:
+		return redactRecords(typed)
+	case []any:
+		redacted := make([]any, len(typed))
+		for index, element := range typed {
+			redacted[index] = Redact(element)
+		}
+		return redacted
+	}
+
+	return value
+}
+
+// redactMap returns a copy of record with the values of sensitive keys masked.
+func redactMap(record map[string]any) map[string]any {
+	if record == nil {
+		return nil
+	}
+	redacted := make(map[string]any, len(record))
+	for key, value := range record {
+		if isSensitiveKey(key) {
+			if secret, ok := value.(string); ok {
+				redacted[key] = Mask(secret)
+			} else if value != nil {
+				redacted[key] = Mask("value")
+			} else {
+				redacted[key] = nil
+			}
+			continue
+		}
+		redacted[key] = Redact(value)
+	}
+
+	return redacted
+}
+
+// redactRecords returns a copy of records with the values of sensitive keys masked.
+func redactRecords(records []map[string]any) []map[string]any {
+	if records == nil {
+		return nil
+	}
+	redacted := make([]map[string]any, len(records))
+	for index, record := range records {
+		redacted[index] = redactMap(record)
+	}
+
+	return redacted
+}
+
+// redacted returns a copy of the response that can be logged.
+func (r RestResponse) redacted() RestResponse {
+	r.Records = redactRecords(r.Records)
+	r.Job = redactMap(r.Job)
+	r.Jobs = redactRecords(r.Jobs)
+
+	return r
+}
+
+// redactBody returns body with the values of sensitive keys masked.  A JSON body is redacted as a map, and re-encoded
+// with sorted keys; in other bodies, "key": "value" and key=value pairs are masked.
+func redactBody(body []byte) string {
+	var value any
+	if err := json.Unmarshal(body, &value); err == nil {
+		if redacted, err := json.Marshal(Redact(value)); err == nil {
+			return string(redacted)
+		}
+	}
+	quotedKeys := make([]string, len(SensitiveKeys))
+	for index, key := range SensitiveKeys {
+		quotedKeys[index] = regexp.QuoteMeta(key)
+	}
+	pattern := regexp.MustCompile(fmt.Sprintf(`(?i)("?[\w-]*(?:%s)[\w-]*"?\s*[:=]\s*)("[^"]*"|[^\s,&}]*)`, strings.Join(quotedKeys, "|")))
+
+	return pattern.ReplaceAllString(string(body), "${1}"+Mask("value"))
+}
+
+// maxBodyInError is the number of bytes of a response body included in an error.
+const maxBodyInError = 512
+
+// truncateBody shortens body to maxBodyInError bytes, noting how many bytes were dropped.
+func truncateBody(body string) string {
+	if len(body) <= maxBodyInError {
+		return body
+	}
+
+	return fmt.Sprintf("%s... (%d more bytes)", strings.ToValidUTF8(body[:maxBodyInError], ""), len(body)-maxBodyInError)
+}
@@ -0,0 +1,81 @@
+package restclient
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{name: "nil", value: nil, want: nil},
+		{name: "string", value: "password", want: "password"},
+		{name: "map",
+			value: map[string]any{"user": "admin", "Password": "secret", "access_token": "abc", "empty_secret": ""},
+			want:  map[string]any{"user": "admin", "Password": "********", "access_token": "********", "empty_secret": ""}},
+		{name: "nested",
+			value: map[string]any{"extravars": map[string]any{"vm": "vm1", "api_key": 42}, "data": []any{map[string]any{"client_secret": "s"}}},
+			want:  map[string]any{"extravars": map[string]any{"vm": "vm1", "api_key": "********"}, "data": []any{map[string]any{"client_secret": "********"}}}},
+		{name: "records",
+			value: []map[string]any{{"token": "t", "id": 1}},
+			want:  []map[string]any{{"token": "********", "id": 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Redact(tt.value); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Redact() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact_sensitiveKeys(t *testing.T) {
+	saved := SensitiveKeys
+	defer func() { SensitiveKeys = saved }()
+	SensitiveKeys = append(SensitiveKeys, "vault_pass")
+
+	value := map[string]any{"vault_pass": "v", "passphrase": "p"}
+	want := map[string]any{"vault_pass": "********", "passphrase": "p"}
+	if got := Redact(value); !reflect.DeepEqual(got, want) {
+		t.Errorf("Redact() = %#v, want %#v", got, want)
+	}
+	if value["vault_pass"] != "v" {
+		t.Errorf("Redact() modified its input: %#v", value)
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "json", body: `{"user": "admin", "password": "secret"}`, want: `{"password":"********","user":"admin"}`},
+		{name: "json_list", body: `[{"token": "t"}]`, want: `[{"token":"********"}]`},
+		{name: "truncated_json", body: `{"user": "admin", "password": "secret", "id"`, want: `{"user": "admin", "password": ********, "id"`},
+		{name: "form", body: "user=admin&api_key=k&id=1", want: "user=admin&api_key=********&id=1"},
+		{name: "html", body: "<html>Bad Gateway</html>", want: "<html>Bad Gateway</html>"},
+		{name: "empty", body: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("redactBody() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateBody(t *testing.T) {
+	short := strings.Repeat("a", maxBodyInError)
+	if got := truncateBody(short); got != short {
+		t.Errorf("truncateBody() = %q, want %q", got, short)
+	}
+	if got, want := truncateBody(short+"bcd"), short+"... (3 more bytes)"; got != want {
+		t.Errorf("truncateBody() = %q, want %q", got, want)
+	}
+}
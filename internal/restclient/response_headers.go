@@ -0,0 +1,73 @@
+package restclient
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// credentialHeaders carry credentials or sessions, they are never captured whatever the allowlist.
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "Www-Authenticate", "Proxy-Authenticate"}
+
+// IsSensitiveHeader reports whether the header carries credentials, or has a sensitive name such as X-Api-Token, ignoring case.
+func IsSensitiveHeader(name string) bool {
+	name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+	for _, header := range credentialHeaders {
+		if name == header {
+			return true
+		}
+	}
+
+	return isSensitiveKey(name)
+}
+
+// capturedHeaders holds the allowlisted headers of the last response, shared by the copies of a RestClient.
+type capturedHeaders struct {
+	names  []string
+	mutex  sync.Mutex
+	values map[string]string
+}
+
+// CaptureResponseHeaders records the headers named in names from each response, see ResponseHeaders.
+// Sensitive headers are never recorded.
+func (r *RestClient) CaptureResponseHeaders(names []string) {
+	allowed := make([]string, 0, len(names))
+	for _, name := range names {
+		if !IsSensitiveHeader(name) {
+			allowed = append(allowed, http.CanonicalHeaderKey(strings.TrimSpace(name)))
+		}
+	}
+	r.responseHeaders = &capturedHeaders{names: allowed, values: map[string]string{}}
+}
+
+// ResponseHeaders returns the captured headers of the last response, by canonical name.
+// It is empty when no header is captured, or when the last response did not include them.
+func (r *RestClient) ResponseHeaders() map[string]string {
+	values := map[string]string{}
+	if r.responseHeaders == nil {
+		return values
+	}
+	r.responseHeaders.mutex.Lock()
+	defer r.responseHeaders.mutex.Unlock()
+	for name, value := range r.responseHeaders.values {
+		values[name] = value
+	}
+
+	return values
+}
+
+// captureResponseHeaders records the allowlisted headers of a response, replacing those of the previous response.
+func (r *RestClient) captureResponseHeaders(headers http.Header) {
+	if r.responseHeaders == nil {
+		return
+	}
+	values := map[string]string{}
+	for _, name := range r.responseHeaders.names {
+		if value := headers.Get(name); value != "" {
+			values[name] = value
+		}
+	}
+	r.responseHeaders.mutex.Lock()
+	r.responseHeaders.values = values
+	r.responseHeaders.mutex.Unlock()
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"time"
 
@@ -16,13 +18,89 @@ import (
 
 // ConnectionProfile describes out to reach a cluster or svm.
 type ConnectionProfile struct {
-	// TODO: add certs in addition to basic authentication
 	// TODO: Add Timeout (currently hardcoded to 10 seconds)
 	Hostname              string
+	Port                  int
+	Scheme                string
 	Username              string
 	Password              string
+	Token                 string
 	ValidateCerts         bool
+	CACert                string
+	ClientCert            string
+	ClientKey             string
+	MinTLSVersion         uint16
+	ProxyURL              string
 	MaxConcurrentRequests int
+	// MaxIdleConns and IdleConnTimeout tune the pool of connections kept open between requests, 0 uses the defaults
+	MaxIdleConns    int
+	IdleConnTimeout time.Duration
+	// Headers are added to each API request, e.g. a tenant header required by an ingress
+	Headers map[string]string
+	// DisableRedirects reports redirects as errors rather than following them
+	DisableRedirects bool
+	// TLSServerName overrides the name used to validate the server certificate, e.g. when Hostname is an IP address
+	TLSServerName string
+	// CompressRequests gzip-compresses large request bodies, for servers or proxies that accept Content-Encoding: gzip
+	CompressRequests bool
+	// NoProxy lists the hosts reached without a proxy, in the NO_PROXY format
+	NoProxy []string
+}
+
+// GoString masks credentials, so that a profile can be logged with %#v.
+func (p ConnectionProfile) GoString() string {
+	type profile ConnectionProfile
+	masked := profile(p)
+	masked.Password = Mask(p.Password)
+	masked.Token = Mask(p.Token)
+	masked.ClientKey = Mask(p.ClientKey)
+	masked.ProxyURL = RedactURL(p.ProxyURL)
+	masked.Headers = RedactHeaders(p.Headers)
+
+	return fmt.Sprintf("%#v", masked)
+}
+
+// Mask hides a secret, an empty value is left empty.
+func Mask(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	return "********"
+}
+
+// IsReservedHeader reports whether a header is set by the client, and cannot be replaced with ConnectionProfile.Headers.
+func IsReservedHeader(name string) bool {
+	return httpclient.IsReservedHeader(name)
+}
+
+// RedactHeaders returns a copy of headers with the values of sensitive headers masked, e.g. X-Api-Token.
+func RedactHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if isSensitiveKey(name) {
+			value = Mask(value)
+		}
+		redacted[name] = value
+	}
+
+	return redacted
+}
+
+// RedactURL hides the password of a URL, or the whole URL when it cannot be parsed.
+func RedactURL(value string) string {
+	if value == "" {
+		return ""
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return Mask(value)
+	}
+
+	return u.Redacted()
 }
 
 // RestClient to interact with the Ansible Forms REST API.
@@ -33,13 +111,33 @@ type RestClient struct {
 	httpClient            httpclient.HTTPClient
 	requestSlots          chan int
 	mode                  string
-	responses             []MockResponse
+	responses             *[]MockResponse
 	jobCompletionTimeOut  int
 	tag                   string
+	retryPolicy           RetryPolicy
+	logTimings            bool
+	// headers are added to each request of this client, see WithHeaders
+	headers map[string]string
+	// responseHeaders records the allowlisted headers of the last response, nil when not enabled, see CaptureResponseHeaders
+	responseHeaders *capturedHeaders
+}
+
+// apiRoot is the path of the Ansible Forms API.
+const apiRoot = "api/v1"
+
+// clientOption customizes a RestClient created by NewClient.
+type clientOption func(*RestClient)
+
+// withTransport sends the requests of the client through transport rather than the shared transport of the profile,
+// e.g. to reach an httptest.Server, or to return canned responses, in tests.
+func withTransport(transport http.RoundTripper) clientOption {
+	return func(r *RestClient) {
+		r.httpClient.SetTransport(transport)
+	}
 }
 
 // NewClient creates a new REST client and a supporting HTTP client.
-func NewClient(ctx context.Context, cxProfile ConnectionProfile, tag string, jobCompletionTimeOut int) (*RestClient, error) {
+func NewClient(ctx context.Context, cxProfile ConnectionProfile, tag string, jobCompletionTimeOut int, options ...clientOption) (*RestClient, error) {
 	var httpProfile httpclient.HTTPProfile
 	err := mapstructure.Decode(cxProfile, &httpProfile)
 	if err != nil {
@@ -47,10 +145,12 @@ func NewClient(ctx context.Context, cxProfile ConnectionProfile, tag string, job
 		tflog.Error(ctx, msg)
 		return nil, errors.New(msg)
 	}
-	httpProfile.APIRoot = "api/v1"
+	httpProfile.APIRoot = apiRoot
+	// 0 means unlimited
 	maxConcurrentRequests := cxProfile.MaxConcurrentRequests
-	if maxConcurrentRequests == 0 {
-		maxConcurrentRequests = 6
+	var requestSlots chan int
+	if maxConcurrentRequests > 0 {
+		requestSlots = make(chan int, maxConcurrentRequests)
 	}
 	client := RestClient{
 		connectionProfile:     cxProfile,
@@ -58,10 +158,13 @@ func NewClient(ctx context.Context, cxProfile ConnectionProfile, tag string, job
 		httpClient:            httpclient.NewClient(ctx, httpProfile, tag),
 		maxConcurrentRequests: maxConcurrentRequests,
 		mode:                  "prod",
-		requestSlots:          make(chan int, maxConcurrentRequests),
+		requestSlots:          requestSlots,
 		jobCompletionTimeOut:  jobCompletionTimeOut,
 		tag:                   tag,
 	}
+	for _, option := range options {
+		option(&client)
+	}
 
 	return &client, nil
 }
@@ -76,7 +179,8 @@ func (r *RestClient) CallCreateMethod(baseURL string, query *RestQuery, body map
 	statusCode, response, err := r.callAPIMethod("POST", baseURL, query, body)
 	if err != nil {
 		tflog.Debug(r.ctx, fmt.Sprintf("CallCreateMethod request failed %#v", statusCode))
-		return statusCode, RestResponse{}, err
+		// the response may describe which fields were rejected.
+		return statusCode, response, err
 	}
 
 	if response.Job != nil {
@@ -126,6 +230,18 @@ func (r *RestClient) CallUpdateMethod(baseURL string, query *RestQuery, body map
 	return statusCode, response, err
 }
 
+// CallReplaceMethod returns response from PUT results.  An error is reported if an error is received.
+// Ansible Forms updates objects with PUT, sending the whole object.
+func (r *RestClient) CallReplaceMethod(baseURL string, query *RestQuery, body map[string]any) (int, RestResponse, error) {
+	statusCode, response, err := r.callAPIMethod("PUT", baseURL, query, body)
+	if err != nil {
+		tflog.Debug(r.ctx, fmt.Sprintf("CallReplaceMethod request failed %#v", statusCode))
+		return statusCode, response, err
+	}
+
+	return statusCode, response, err
+}
+
 // CallDeleteMethod returns response from DELETE results.  An error is reported if an error is received.
 func (r *RestClient) CallDeleteMethod(baseURL string, query *RestQuery, body map[string]any) (int, RestResponse, error) {
 	if query == nil {
@@ -150,7 +266,7 @@ func (r *RestClient) GetNilOrOneRecord(baseURL string, query *RestQuery, body ma
 		return statusCode, nil, err
 	}
 	if response.NumRecords > 1 {
-		msg := fmt.Sprintf("received 2 or more records when only one is expected - statusCode %d, err=%#v, response=%#v", statusCode, err, response)
+		msg := fmt.Sprintf("received 2 or more records when only one is expected - statusCode %d, err=%#v, response=%#v", statusCode, err, response.redacted())
 		tflog.Error(r.ctx, msg)
 		return statusCode, nil, errors.New(msg)
 	}
@@ -161,6 +277,17 @@ func (r *RestClient) GetNilOrOneRecord(baseURL string, query *RestQuery, body ma
 	return statusCode, nil, err
 }
 
+// GetAllowNotFound returns nil if no record is found or a single record, as GetNilOrOneRecord, and nil without error
+// when the object does not exist (404), so that Read can remove an object deleted outside of Terraform from state.
+func (r *RestClient) GetAllowNotFound(baseURL string, query *RestQuery, body map[string]any) (int, map[string]any, error) {
+	statusCode, record, err := r.GetNilOrOneRecord(baseURL, query, body)
+	if statusCode == http.StatusNotFound || errors.Is(err, ErrNotFound) {
+		return statusCode, nil, nil
+	}
+
+	return statusCode, record, err
+}
+
 // GetZeroOrMoreRecords returns a list of records.
 func (r *RestClient) GetZeroOrMoreRecords(baseURL string, query *RestQuery, body map[string]any) (int, []map[string]any, error) {
 	statusCode, response, err := r.callAPIMethod("GET", baseURL, query, body)
@@ -171,6 +298,90 @@ func (r *RestClient) GetZeroOrMoreRecords(baseURL string, query *RestQuery, body
 	return statusCode, response.Records, err
 }
 
+// maxPages caps the number of pages GetRecordPages follows, in case the next links never end, e.g. with a page number increasing forever.
+const maxPages = 1000
+
+// GetAllRecords returns a list of records, following _links.next until all pages are read, see GetRecordPages.
+// Records with the same id or uuid are only returned once, in case the server repeats them across pages.
+func (r *RestClient) GetAllRecords(baseURL string, query *RestQuery, body map[string]any) (int, []map[string]any, error) {
+	var records []map[string]any
+	statusCode, err := r.GetRecordPages(baseURL, query, body, func(page []map[string]any) bool {
+		records = append(records, page...)
+		return true
+	})
+	if err != nil {
+		return statusCode, nil, err
+	}
+
+	return statusCode, UniqueRecords(records), nil
+}
+
+// GetRecordPages passes the records of each page to visit, following _links.next until all pages are read or visit returns false,
+// so that a caller needing a limited number of records does not read the other pages.
+// A next page that was already read ends the list, and the list is truncated with a warning after maxPages pages.
+func (r *RestClient) GetRecordPages(baseURL string, query *RestQuery, body map[string]any, visit func(records []map[string]any) bool) (int, error) {
+	visited := map[string]bool{}
+	for page := 1; ; page++ {
+		statusCode, response, err := r.callAPIMethod("GET", baseURL, query, body)
+		if err != nil {
+			return statusCode, err
+		}
+		if !visit(response.Records) || response.NextHref == "" {
+			return statusCode, nil
+		}
+		if visited[response.NextHref] {
+			tflog.Warn(r.ctx, fmt.Sprintf("next page %s was already read, ignoring it", response.NextHref))
+			return statusCode, nil
+		}
+		if page >= maxPages {
+			tflog.Warn(r.ctx, fmt.Sprintf("more than %d pages returned for GET %s, ignoring the next pages", maxPages, baseURL))
+			return statusCode, nil
+		}
+		visited[response.NextHref] = true
+		baseURL, query, err = r.parseHref(response.NextHref)
+		if err != nil {
+			return statusCode, err
+		}
+	}
+}
+
+// parseHref converts a link returned by the server into a baseURL and query.
+func (r *RestClient) parseHref(href string) (string, *RestQuery, error) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid link %s: %w", href, err)
+	}
+	query := r.NewQuery()
+	query.Values = u.Query()
+	baseURL := strings.TrimPrefix(u.Path, "/")
+	baseURL = strings.TrimPrefix(baseURL, apiRoot+"/")
+
+	return baseURL, query, nil
+}
+
+// UniqueRecords removes records with an id or uuid already present in an earlier record.
+func UniqueRecords(records []map[string]any) []map[string]any {
+	seen := map[string]bool{}
+	unique := make([]map[string]any, 0, len(records))
+	for _, record := range records {
+		key := ""
+		if id, ok := record["id"]; ok {
+			key = fmt.Sprintf("id:%v", id)
+		} else if uuid, ok := record["uuid"]; ok {
+			key = fmt.Sprintf("uuid:%v", uuid)
+		}
+		if key != "" {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		unique = append(unique, record)
+	}
+
+	return unique
+}
+
 // Wait waits for job to finish.
 func (r *RestClient) Wait(uuid string) (int, RestResponse, error) {
 	timeRemaining := r.jobCompletionTimeOut
@@ -187,7 +398,7 @@ func (r *RestClient) Wait(uuid string) (int, RestResponse, error) {
 		}
 		var job Job
 		if err := mapstructure.Decode(response, &job); err != nil {
-			tflog.Error(r.ctx, fmt.Sprintf("Read job data - decode error: %s, data: %#v", err, response))
+			tflog.Error(r.ctx, fmt.Sprintf("Read job data - decode error: %s, data: %#v", err, Redact(response)))
 			return statusCode, RestResponse{}, err
 		}
 		if job.State == "queued" || job.State == "running" || job.State == "paused" {
@@ -219,36 +430,147 @@ func (r *RestClient) callAPIMethod(method string, baseURL string, query *RestQue
 	if r.mode == "mock" {
 		return r.mockCallAPIMethod(method, baseURL, query, body)
 	}
-	r.waitForAvailableSlot()
-	defer r.releaseSlot()
-
 	values := url.Values{}
 	if query != nil {
 		values = query.Values
 	}
 
-	statusCode, response, httpClientErr := r.httpClient.Do(baseURL, &httpclient.Request{
-		Method: method,
-		Body:   body,
-		Query:  values,
-	})
+	tflog.Debug(r.ctx, fmt.Sprintf("calling %s %s", method, baseURL), map[string]any{"body": Redact(body)})
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		if err := r.waitForAvailableSlot(); err != nil {
+			return r.unmarshalResponse(-1, nil, err)
+		}
+		start := time.Now()
+		statusCode, response, headers, httpClientErr := r.httpClient.Do(baseURL, &httpclient.Request{
+			Method:  method,
+			Body:    body,
+			Query:   values,
+			Headers: r.headers,
+		})
+		r.logTiming(method, baseURL, attempt, statusCode, time.Since(start))
+		r.releaseSlot()
+
+		// the session may expire during a long apply, log in again once and send the request again
+		if statusCode == http.StatusUnauthorized && httpClientErr == nil && !reauthenticated {
+			reauthenticated = true
+			tflog.Info(r.ctx, fmt.Sprintf("%s %s returned statusCode 401, logging in again", method, baseURL))
+			if err := r.httpClient.Reauthenticate(); err != nil {
+				r.captureResponseHeaders(headers)
+				return r.sessionExpired(statusCode, headers, response, err)
+			}
+			// the request sent after logging in again is not counted as a retry
+			attempt--
+			continue
+		}
+		if attempt >= r.retryPolicy.MaxRetries || !shouldRetry(method, statusCode, httpClientErr) {
+			r.captureResponseHeaders(headers)
+			// TODO: handle async calls (job in response)
+			return r.decodeResponse(statusCode, headers, response, httpClientErr)
+		}
+		wait := r.retryWait(method, baseURL, attempt, statusCode, headers)
+		tflog.Debug(r.ctx, fmt.Sprintf("retrying %s %s in %s, attempt %d of %d - statusCode %d, err %v", method, baseURL, wait, attempt+1, r.retryPolicy.MaxRetries, statusCode, httpClientErr))
+		select {
+		case <-r.ctx.Done():
+			return r.unmarshalResponse(statusCode, nil, r.ctx.Err())
+		case <-time.After(wait):
+		}
+	}
+}
 
-	// TODO: error handling for HTTTP status code >=300
-	// TODO: handle async calls (job in response)
-	return r.unmarshalResponse(statusCode, response, httpClientErr)
+// sessionExpired decodes the 401 response of a request, and reports that logging in again failed with reauthErr.
+func (r *RestClient) sessionExpired(statusCode int, headers http.Header, responseJSON []byte, reauthErr error) (int, RestResponse, error) {
+	tflog.Error(r.ctx, fmt.Sprintf("session expired and re-auth failed: %s", reauthErr))
+	statusCode, response, err := r.decodeResponse(statusCode, headers, responseJSON, nil)
+	if err == nil {
+		err = fmt.Errorf("statusCode %d", statusCode)
+	}
+	if response.ErrorType == "" {
+		response.ErrorType = ErrorTypeStatusCode
+	}
+
+	return statusCode, response, &RestClientError{StatusCode: statusCode, ErrorType: response.ErrorType, RestError: response.RestError,
+		err: fmt.Errorf("session expired and re-auth failed: %w, request error: %s", reauthErr, err)}
 }
 
-func (r *RestClient) waitForAvailableSlot() {
-	r.requestSlots <- 1
+// WithHeaders returns a copy of the client that adds headers to each of its requests, e.g. an Idempotency-Key for a single POST.
+// The client itself is not changed.
+func (r RestClient) WithHeaders(headers map[string]string) RestClient {
+	merged := make(map[string]string, len(r.headers)+len(headers))
+	for name, value := range r.headers {
+		merged[name] = value
+	}
+	for name, value := range headers {
+		merged[name] = value
+	}
+	r.headers = merged
+
+	return r
+}
+
+// SetLogTimings logs the timing of each request at Info level rather than Debug level.
+func (r *RestClient) SetLogTimings(logTimings bool) {
+	r.logTimings = logTimings
+}
+
+// logTiming logs the method, path, status code, and latency of a request, without its query or body.
+// The latency includes reading the response, but not the wait for a request slot.
+func (r *RestClient) logTiming(method string, baseURL string, attempt int, statusCode int, elapsed time.Duration) {
+	msg := fmt.Sprintf("%s %s: statusCode %d in %dms", method, baseURL, statusCode, elapsed.Milliseconds())
+	fields := map[string]any{
+		"method":      method,
+		"path":        baseURL,
+		"status_code": statusCode,
+		"elapsed_ms":  elapsed.Milliseconds(),
+		"attempt":     attempt + 1,
+	}
+	if r.logTimings {
+		tflog.Info(r.ctx, msg, fields)
+		return
+	}
+	tflog.Debug(r.ctx, msg, fields)
+}
+
+// SetRequestTimeout bounds the time spent on each HTTP request, 0 uses the default timeout.
+func (r *RestClient) SetRequestTimeout(timeout time.Duration) {
+	r.httpClient.SetRequestTimeout(timeout)
+}
+
+// SetUserAgent sets the User-Agent header sent with each HTTP request.
+func (r *RestClient) SetUserAgent(userAgent string) {
+	r.httpClient.SetUserAgent(userAgent)
+}
+
+// SetRequestSlots shares a semaphore between the clients of a connection profile, so that MaxConcurrentRequests
+// applies to the profile rather than to each client.  The capacity of slots is the maximum number of requests in flight.
+func (r *RestClient) SetRequestSlots(slots chan int) {
+	r.requestSlots = slots
+}
+
+// waitForAvailableSlot waits for a request slot, and returns the error of the client context when it is done first, e.g. on
+// operation_timeout or when Terraform is interrupted.  A slot is only taken when the error is nil.
+func (r *RestClient) waitForAvailableSlot() error {
+	if r.requestSlots == nil {
+		return nil
+	}
+	select {
+	case r.requestSlots <- 1:
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
 }
 
 func (r *RestClient) releaseSlot() {
+	if r.requestSlots == nil {
+		return
+	}
 	<-r.requestSlots
 }
 
 // Equals is a test function for Unit Testing
 func (r *RestClient) Equals(r2 *RestClient) (ok bool, firstDiff string) {
-	if r.connectionProfile != r2.connectionProfile {
+	if !reflect.DeepEqual(r.connectionProfile, r2.connectionProfile) {
 		return false, fmt.Sprintf("expected %#v, got %#v", r.connectionProfile, r2.connectionProfile)
 	}
 	if r.tag != r2.tag {
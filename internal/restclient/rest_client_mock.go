@@ -27,23 +27,24 @@ func NewMockedRestClient(responses []MockResponse) (*RestClient, error) {
 		panic(err)
 	}
 	newRestClient.mode = "mock"
-	newRestClient.responses = responses
+	// the client is often passed by value, share the responses so they are consumed only once.
+	newRestClient.responses = &responses
 
 	return newRestClient, nil
 }
 
 func (r *RestClient) mockCallAPIMethod(method string, baseURL string, query *RestQuery, body map[string]any) (int, RestResponse, error) {
-	if len(r.responses) == 0 {
+	if len(*r.responses) == 0 {
 		panic(fmt.Sprintf("Unexpected request: %s %s", method, baseURL))
 	}
-	expectedResponse := r.responses[0]
+	expectedResponse := (*r.responses)[0]
 	if expectedResponse.ExpectedMethod != method || expectedResponse.ExpectedURL != baseURL {
-		if len(r.responses) == 0 {
+		if len(*r.responses) == 0 {
 			panic(fmt.Sprintf("Unexpected request: %s %s, expecting %s %s", method, baseURL, expectedResponse.ExpectedMethod, expectedResponse.ExpectedURL))
 		}
 	}
 	// remove element now that we know it is consumed
-	r.responses = r.responses[1:]
+	*r.responses = (*r.responses)[1:]
 
 	return expectedResponse.StatusCode, expectedResponse.Response, expectedResponse.Err
 }
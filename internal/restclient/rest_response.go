@@ -2,7 +2,13 @@ package restclient
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/mitchellh/mapstructure"
@@ -15,6 +21,134 @@ type RestError struct {
 	Target  string
 }
 
+// Detail formats the error as labeled lines, for diagnostics.  Empty fields are omitted.
+func (e RestError) Detail() string {
+	lines := []string{}
+	for _, field := range []struct{ label, value string }{{"Code", e.Code}, {"Message", e.Message}, {"Target", e.Target}} {
+		if field.value != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", field.label, field.value))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// HasError reports whether the server reported an error.
+// A code of 0 is not an error on its own, but a message with a 0 code is.
+func (e RestError) HasError() bool {
+	return (e.Code != "" && e.Code != "0") || e.Message != ""
+}
+
+// restErrorCodeHook accepts both string and numeric JSON error codes, e.g. {"code": 0} and {"code": "0"}.
+func restErrorCodeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeOf(RestError{}) || from.Kind() != reflect.Map {
+		return data, nil
+	}
+	errorMap, ok := data.(map[string]any)
+	if !ok {
+		return data, nil
+	}
+	var code string
+	switch value := errorMap["code"].(type) {
+	case float64:
+		code = strconv.FormatFloat(value, 'f', -1, 64)
+	case int:
+		code = strconv.Itoa(value)
+	case int64:
+		code = strconv.FormatInt(value, 10)
+	case json.Number:
+		code = value.String()
+	default:
+		return data, nil
+	}
+	converted := make(map[string]any, len(errorMap))
+	for k, v := range errorMap {
+		converted[k] = v
+	}
+	converted["code"] = code
+
+	return converted, nil
+}
+
+// decodeRestResponse decodes input into output, converting numeric error codes to strings.
+func decodeRestResponse(input any, output any, metadata *mapstructure.Metadata) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: restErrorCodeHook,
+		Metadata:   metadata,
+		Result:     output,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
+// ErrorType classifies why a request failed.  The values are kept stable, they may be logged or compared by callers.
+type ErrorType string
+
+// ErrorType values, empty when there is no error.
+const (
+	// ErrorTypeHTTP is set when no response was received
+	ErrorTypeHTTP ErrorType = "http"
+	// ErrorTypeDecompress is set when a compressed body cannot be decompressed
+	ErrorTypeDecompress ErrorType = "bad_response_decompress"
+	// ErrorTypeDecodeJSON is set when the body is not JSON
+	ErrorTypeDecodeJSON ErrorType = "bad_response_decode_json"
+	// ErrorTypeDecodeInterface and ErrorTypeDecodeRaw are set when the JSON body does not have the expected structure
+	ErrorTypeDecodeInterface ErrorType = "bad_response_decode_interface"
+	ErrorTypeDecodeRaw       ErrorType = "bad_response_decode_raw"
+	// ErrorTypeRESTError is set when the body reports an error
+	ErrorTypeRESTError ErrorType = "rest_error"
+	// ErrorTypeStatusCode is set when the status code reports an error, without details in the body
+	ErrorTypeStatusCode ErrorType = "statuscode_error"
+	// ErrorTypeNonJSON is set when the Content-Type of the body is not JSON, e.g. an HTML page when the hostname or port is wrong
+	ErrorTypeNonJSON ErrorType = "non_json_response"
+	// ErrorTypeStream is set when a streamed body cannot be read or consumed, see GetStream
+	ErrorTypeStream ErrorType = "stream_error"
+)
+
+// RestClientError is the error returned for a failed request, so that callers can tell failures apart with errors.As,
+// e.g. a 404 status code from a rest_error.  Error returns the same message as the underlying error, which Unwrap returns.
+type RestClientError struct {
+	StatusCode int
+	ErrorType  ErrorType
+	RestError  RestError
+	// Location is the target of a redirect that was not followed
+	Location string
+	err      error
+}
+
+// Error describes the failure.
+func (e *RestClientError) Error() string {
+	return e.err.Error()
+}
+
+// ErrNotFound matches, with errors.Is, the error returned for a 404 status code.
+var ErrNotFound = errors.New("not found")
+
+// ErrRedirect matches, with errors.Is, the error returned for a redirect that was not followed.
+var ErrRedirect = errors.New("redirect not followed")
+
+// Is reports whether target is ErrNotFound and the status code is 404, or target is ErrRedirect and the status code is 3xx.
+func (e *RestClientError) Is(target error) bool {
+	return (target == ErrNotFound && e.StatusCode == http.StatusNotFound) || (target == ErrRedirect && isRedirect(e.StatusCode))
+}
+
+// Unwrap returns the underlying error, e.g. context.Canceled when the request was aborted.
+func (e *RestClientError) Unwrap() error {
+	return e.err
+}
+
+// newRestClientError wraps err with the status code, error type, and REST error of response, nil when err is nil.
+func newRestClientError(response RestResponse, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &RestClientError{StatusCode: response.StatusCode, ErrorType: response.ErrorType, RestError: response.RestError, err: err}
+}
+
 // RestResponse to return a list of records (can be empty) and/or errors.
 type RestResponse struct {
 	NumRecords int `mapstructure:"num_records"`
@@ -22,9 +156,67 @@ type RestResponse struct {
 	RestError  RestError `mapstructure:"error"`
 	StatusCode int
 	HTTPError  string
-	ErrorType  string
+	ErrorType  ErrorType
 	Job        map[string]any
 	Jobs       []map[string]any
+	// NextHref is the link to the next page of records, empty on the last page.
+	NextHref string
+	// RawBody is the redacted response body, only set when it could not be decoded.
+	RawBody string
+}
+
+// nextHref returns _links.next.href when present.
+func nextHref(dataMap map[string]any) string {
+	links, _ := dataMap["_links"].(map[string]any)
+	next, _ := links["next"].(map[string]any)
+	href, _ := next["href"].(string)
+
+	return href
+}
+
+// decodeResponse decompresses the response body when needed, and converts it with unmarshalResponse.
+// A redirect that was not followed is reported with its Location, as its body is usually not JSON.
+// A body that is not JSON, according to its Content-Type and to its contents, is reported with its Content-Type and its start,
+// rather than as a JSON decode error.
+func (r *RestClient) decodeResponse(statusCode int, headers http.Header, responseJSON []byte, httpClientErr error) (int, RestResponse, error) {
+	if httpClientErr == nil && isRedirect(statusCode) {
+		location := headers.Get("Location")
+		err := redirectError(statusCode, location)
+		tflog.Error(r.ctx, fmt.Sprintf("redirect not followed: %s", err))
+		response := RestResponse{Records: []map[string]any{}, StatusCode: statusCode, ErrorType: ErrorTypeStatusCode}
+		return statusCode, response, &RestClientError{StatusCode: statusCode, ErrorType: response.ErrorType, Location: RedactURL(location), err: err}
+	}
+	if httpClientErr == nil {
+		decompressed, err := decompressBody(headers, responseJSON)
+		if err != nil {
+			tflog.Error(r.ctx, fmt.Sprintf("unable to decompress response, statusCode %d, error=%s", statusCode, err))
+			response := RestResponse{Records: []map[string]any{}, StatusCode: statusCode, ErrorType: ErrorTypeDecompress}
+			return statusCode, response, newRestClientError(response, err)
+		}
+		responseJSON = decompressed
+		if contentType := headers.Get("Content-Type"); !isJSONContentType(contentType) && len(responseJSON) != 0 && !json.Valid(responseJSON) {
+			tflog.Error(r.ctx, fmt.Sprintf("response is not JSON, statusCode %d, Content-Type %s", statusCode, contentType))
+			response := RestResponse{Records: []map[string]any{}, StatusCode: statusCode, ErrorType: ErrorTypeNonJSON}
+			return statusCode, response, response.withRawBody(responseJSON,
+				fmt.Errorf("expected a JSON response, got Content-Type %s with statusCode %d, check that hostname and port point to the Ansible Forms API", contentType, statusCode))
+		}
+	}
+
+	return r.unmarshalResponse(statusCode, responseJSON, httpClientErr)
+}
+
+// isJSONContentType reports whether contentType is JSON, such as application/json or application/problem+json.
+// An empty Content-Type is accepted, and the body is decoded as JSON.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
 }
 
 // unmarshalResponse converts the REST response into a structure with a list of 0 or more records.
@@ -42,18 +234,23 @@ func (r *RestClient) unmarshalResponse(statusCode int, responseJSON []byte, http
 	}
 	if httpClientErr != nil {
 		emptyResponse.HTTPError = httpClientErr.Error()
-		emptyResponse.ErrorType = "http"
-		return statusCode, emptyResponse, httpClientErr
+		emptyResponse.ErrorType = ErrorTypeHTTP
+		return statusCode, emptyResponse, newRestClientError(emptyResponse, httpClientErr)
 	}
 
 	// We don't know which fields are present or not, and fields may not be in a record, so just use any
 	var dataMap map[string]any
 	if err := json.Unmarshal(responseJSON, &dataMap); err != nil {
-		tflog.Error(r.ctx, fmt.Sprintf("unable to unmarshall response, this may be expected when statusCode %d >= 300, unmarshall error=%s, response=%#v", statusCode, err, responseJSON))
-		emptyResponse.ErrorType = "bad_response_decode_json"
-		return statusCode, emptyResponse, err
+		tflog.Error(r.ctx, fmt.Sprintf("unable to unmarshall response, this may be expected when statusCode %d >= 300, unmarshall error=%s, response=%q", statusCode, err, truncateBody(redactBody(responseJSON))))
+		if statusCode >= 300 {
+			// report the status code rather than the decode error
+			response, err := r.checkRestErrors(statusCode, emptyResponse)
+			return statusCode, response, err
+		}
+		emptyResponse.ErrorType = ErrorTypeDecodeJSON
+		return statusCode, emptyResponse, emptyResponse.withRawBody(responseJSON, err)
 	}
-	tflog.Debug(r.ctx, fmt.Sprintf("dataMap %#v", dataMap))
+	tflog.Debug(r.ctx, fmt.Sprintf("dataMap %#v", Redact(dataMap)))
 
 	// The returned REST response may or may not contain records.
 	// If records is not present, the contents will show in Other.
@@ -67,62 +264,159 @@ func (r *RestClient) unmarshalResponse(statusCode int, responseJSON []byte, http
 	}
 
 	var rawResponse restStagedResponse
+	redactedRawResponse := func() restStagedResponse {
+		redacted := rawResponse
+		redacted.Records = redactRecords(rawResponse.Records)
+		redacted.Job = redactMap(rawResponse.Job)
+		redacted.Jobs = redactRecords(rawResponse.Jobs)
+		redacted.Other = redactMap(rawResponse.Other)
+		return redacted
+	}
 	var metadata mapstructure.Metadata
-	if err := mapstructure.DecodeMetadata(dataMap, &rawResponse, &metadata); err != nil {
-		tflog.Error(r.ctx, fmt.Sprintf("unable to format raw response, this may be expected when statusCode %d >= 300, unmarshall error=%s, response=%#v", statusCode, err, dataMap))
-		emptyResponse.ErrorType = "bad_response_decode_interface"
-		return statusCode, emptyResponse, err
+	if err := decodeRestResponse(dataMap, &rawResponse, &metadata); err != nil {
+		tflog.Error(r.ctx, fmt.Sprintf("unable to format raw response, this may be expected when statusCode %d >= 300, unmarshall error=%s, response=%#v", statusCode, err, Redact(dataMap)))
+		if statusCode >= 300 {
+			// the error may still be readable, even though the body does not have the expected structure
+			emptyResponse.RestError = restErrorFromBody(dataMap)
+			response, err := r.checkRestErrors(statusCode, emptyResponse)
+			return statusCode, response, err
+		}
+		emptyResponse.ErrorType = ErrorTypeDecodeInterface
+		return statusCode, emptyResponse, emptyResponse.withRawBody(responseJSON, err)
 	}
 
-	tflog.Debug(r.ctx, fmt.Sprintf("rawResponse %#v, metadata %#v", rawResponse, metadata))
+	tflog.Debug(r.ctx, fmt.Sprintf("rawResponse %#v, metadata %#v", redactedRawResponse(), metadata))
 
-	// If Other is present, add it to records.
-	// But ignore it if we already have some records.
-	// Other will always have 1 element called _link, so only do this if Other has more than 1 element
-	// Examples:
-	// {NumRecords:0 Records:[] Error:{Code: Message: Target:} Job:map[] Jobs:[] Other:map[_links:map[self:map[href:/api/cluster/schedules?fields=name%2Cuuid%2Ccron%2Cinterval%2Ctype%2Cscope&name=mytest]]]}
-	// {NumRecords:0 Records:[] Error:{Code: Message: Target:} Job:map[] Jobs:[] Other:map[_links:map[self:map[href:/api/cluster]] certificate:map[_links:map[self:map[href:/api/security/certificates/2f632ea7-92cd-11ed-8f2b-005056b3357c]] uuid:2f632ea7-92cd-11ed-8f2b-005056b3357c] metric:map[duration:PT15S iops:map[other:0 read:0 total:0 write:0] latency:map[other:0 read:0 total:0 write:0] status:ok throughput:map[other:0 read:0 total:0 write:0] timestamp:2023-03-16T18:36:30Z] name:laurentncluster-2 peering_policy:map[authentication_required:true encryption_required:false minimum_passphrase_length:8] san_optimized:false statistics:map[iops_raw:map[other:0 read:0 total:0 write:0] latency_raw:map[other:0 read:0 total:0 write:0] status:ok throughput_raw:map[other:0 read:0 total:0 write:0] timestamp:2023-03-16T18:36:31Z] timezone:map[name:Etc/UTC] uuid:2115008a-92cd-11ed-8f2b-005056b3357c version:map[full:NetApp Release Metropolitan__9.11.1: Sat Dec 10 19:08:07 UTC 2022 generation:9 major:11 minor:1]]}
-	if rawResponse.NumRecords == 0 && len(rawResponse.Records) == 0 && len(rawResponse.Other) > 1 {
+	// A response without num_records or records is a single object, e.g. the {"status", "message", "data"} envelope of Ansible Forms,
+	// it is returned as a record unless it only holds _links.
+	if !isRecordList(metadata) && hasRecordFields(rawResponse.Other) {
 		rawResponse.NumRecords = 1
 		rawResponse.Records = append(rawResponse.Records, rawResponse.Other)
 	}
 
 	var finalResponse RestResponse
 	if err := mapstructure.DecodeMetadata(rawResponse, &finalResponse, &metadata); err != nil {
-		tflog.Error(r.ctx, fmt.Sprintf("unable to format final response - statusCode %d, http err=%#v, decode error=%s, response=%#v", statusCode, httpClientErr, err, rawResponse))
-		emptyResponse.ErrorType = "bad_response_decode_raw"
-		return statusCode, emptyResponse, err
+		tflog.Error(r.ctx, fmt.Sprintf("unable to format final response - statusCode %d, http err=%#v, decode error=%s, response=%#v", statusCode, httpClientErr, err, redactedRawResponse()))
+		emptyResponse.ErrorType = ErrorTypeDecodeRaw
+		return statusCode, emptyResponse, emptyResponse.withRawBody(responseJSON, err)
 	}
 
 	// If we reached this point, the only possible errors are a bad HTTP status code and/or a REST error encoded in the paybload
 	finalResponse.StatusCode = statusCode
+	finalResponse.NextHref = nextHref(dataMap)
+	if statusCode >= 300 && !finalResponse.RestError.HasError() {
+		finalResponse.RestError = restErrorFromBody(dataMap)
+	}
 	finalResponse, err := r.checkRestErrors(statusCode, finalResponse)
-	tflog.Debug(r.ctx, fmt.Sprintf("finalResponse %#v, metadata %#v", finalResponse, metadata))
+	tflog.Debug(r.ctx, fmt.Sprintf("finalResponse %#v, metadata %#v", finalResponse.redacted(), metadata))
 
 	return statusCode, finalResponse, err
 }
 
+// withRawBody sets RawBody to the redacted body, and adds the start of it to the decode error, to show what failed to parse.
+func (r *RestResponse) withRawBody(body []byte, err error) error {
+	r.RawBody = redactBody(body)
+
+	return newRestClientError(*r, fmt.Errorf("%w, response body: %q", err, truncateBody(r.RawBody)))
+}
+
+// restErrorFromBody extracts the error of an error response: error.code and error.message, an error string,
+// or the message of the {"status", "message", "data"} envelope of Ansible Forms, with data.error when present.
+func restErrorFromBody(dataMap map[string]any) RestError {
+	var restError RestError
+	switch value := dataMap["error"].(type) {
+	case map[string]any:
+		restError.Code = stringOrNumber(value["code"])
+		restError.Message = stringOrNumber(value["message"])
+		restError.Target = stringOrNumber(value["target"])
+		return restError
+	case string:
+		restError.Message = value
+		return restError
+	}
+	restError.Message = stringOrNumber(dataMap["message"])
+	if data, ok := dataMap["data"].(map[string]any); ok {
+		if detail := stringOrNumber(data["error"]); detail != "" {
+			restError.Message = strings.TrimPrefix(restError.Message+": "+detail, ": ")
+		}
+	}
+
+	return restError
+}
+
+// stringOrNumber formats a string or a JSON number, other values are ignored.
+func stringOrNumber(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+
+	return ""
+}
+
+// isRecordList reports whether num_records or records were decoded, using the metadata of the staged response.
+func isRecordList(metadata mapstructure.Metadata) bool {
+	for _, key := range metadata.Keys {
+		if key == "num_records" || key == "Records" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasRecordFields reports whether other has fields besides _links.
+func hasRecordFields(other map[string]any) bool {
+	for key := range other {
+		if key != "_links" {
+			return true
+		}
+	}
+
+	return false
+}
+
 // check for statusCode and RestError
 func (r *RestClient) checkRestErrors(statusCode int, response RestResponse) (RestResponse, error) {
 	var err error
-	if response.RestError.Code != "0" && response.RestError.Code != "" {
-		response.ErrorType = "rest_error"
-		err = fmt.Errorf("REST reported error %#v, statusCode: %d", response.RestError, statusCode)
+	if response.RestError.HasError() {
+		response.ErrorType = ErrorTypeRESTError
+		err = fmt.Errorf("REST reported error, statusCode: %d\n%s", statusCode, response.RestError.Detail())
 	} else if err = r.checkStatusCode(statusCode); err != nil {
-		response.ErrorType = "statuscode_error"
+		response.ErrorType = ErrorTypeStatusCode
 	}
 	if err != nil {
-		tflog.Error(r.ctx, fmt.Sprintf("checkRestError: %s, statusCode %d, response: %#v", err, statusCode, response))
+		tflog.Error(r.ctx, fmt.Sprintf("checkRestError: %s, statusCode %d, response: %#v", err, statusCode, response.redacted()))
 	}
 
-	return response, err
+	return response, newRestClientError(response, err)
 }
 
 // checkStatusCode checks and validates the statusCode
 func (r *RestClient) checkStatusCode(statusCode int) error {
+	if isRedirect(statusCode) {
+		return redirectError(statusCode, "")
+	}
 	if statusCode >= 300 || statusCode < 200 {
 		return fmt.Errorf("statusCode indicates error, without details: %d", statusCode)
 	}
 
 	return nil
 }
+
+// isRedirect reports whether statusCode is in the 3xx range.
+func isRedirect(statusCode int) bool {
+	return statusCode >= 300 && statusCode < 400
+}
+
+// redirectError describes a redirect that was not followed, naming its Location when known, so that the hostname can be fixed.
+func redirectError(statusCode int, location string) error {
+	if location == "" {
+		return fmt.Errorf("statusCode %d indicates a redirect, without a Location header, check the hostname and any proxy in front of Ansible Forms", statusCode)
+	}
+
+	return fmt.Errorf("statusCode %d indicates the endpoint moved to %s, update the hostname of the connection profile, "+
+		"or renew the session if this is a login page of a proxy", statusCode, RedactURL(location))
+}
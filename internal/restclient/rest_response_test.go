@@ -1,13 +1,17 @@
 package restclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -42,13 +46,13 @@ func TestRestClient_unmarshalResponse(t *testing.T) {
 		RestError:  restError,
 		StatusCode: 400,
 		HTTPError:  "",
-		ErrorType:  "rest_error",
+		ErrorType:  ErrorTypeRESTError,
 	}
 	responseStatusCodeError := RestResponse{
 		NumRecords: 0,
 		Records:    []map[string]any(nil),
 		StatusCode: 400,
-		ErrorType:  "statuscode_error",
+		ErrorType:  ErrorTypeStatusCode,
 	}
 	rawResponseRestError := struct {
 		Error RestError
@@ -93,9 +97,9 @@ func TestRestClient_unmarshalResponse(t *testing.T) {
 		want1   RestResponse
 		wantErr bool
 	}{
-		{name: "error_no_json", args: args{}, want: 0, want1: RestResponse{ErrorType: "bad_response_decode_json", Records: []map[string]any{}}, wantErr: true},
-		{name: "error_mismatch_json", args: args{statusCode: 200, responseJSON: badJSON}, want: 200, want1: RestResponse{ErrorType: "bad_response_decode_interface", Records: []map[string]any{}, StatusCode: 200}, wantErr: true},
-		{name: "error_http_error", args: args{httpClientErr: genericError}, want: 0, want1: RestResponse{HTTPError: genericError.Error(), ErrorType: "http", Records: []map[string]any{}}, wantErr: true},
+		{name: "error_no_json", args: args{}, want: 0, want1: RestResponse{ErrorType: ErrorTypeDecodeJSON, Records: []map[string]any{}}, wantErr: true},
+		{name: "error_mismatch_json", args: args{statusCode: 200, responseJSON: badJSON}, want: 200, want1: RestResponse{ErrorType: ErrorTypeDecodeInterface, Records: []map[string]any{}, StatusCode: 200, RawBody: string(badJSON)}, wantErr: true},
+		{name: "error_http_error", args: args{httpClientErr: genericError}, want: 0, want1: RestResponse{HTTPError: genericError.Error(), ErrorType: ErrorTypeHTTP, Records: []map[string]any{}}, wantErr: true},
 		{name: "json_unmarshalled", args: args{statusCode: 200, responseJSON: responseJSON}, want: 200, want1: response, wantErr: false},
 		{name: "json_unmarshalled_other", args: args{statusCode: 200, responseJSON: responseJSONOther}, want: 200, want1: responseOthers, wantErr: false},
 		{name: "rest_error", args: args{statusCode: 400, responseJSON: responseJSONRestError}, want: 400, want1: responseRestError, wantErr: true},
@@ -124,3 +128,243 @@ func TestRestClient_unmarshalResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestRestError_Detail(t *testing.T) {
+	tests := []struct {
+		name      string
+		restError RestError
+		want      string
+	}{
+		{name: "all_fields", restError: RestError{Code: "4", Message: "form not found", Target: "formName"}, want: "Code: 4\nMessage: form not found\nTarget: formName"},
+		{name: "no_target", restError: RestError{Code: "4", Message: "form not found"}, want: "Code: 4\nMessage: form not found"},
+		{name: "empty", restError: RestError{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.restError.Detail(); got != tt.want {
+				t.Errorf("RestError.Detail() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestClient_unmarshalResponse_errorCode(t *testing.T) {
+	tests := []struct {
+		name          string
+		responseJSON  string
+		wantCode      string
+		wantErrorType ErrorType
+		wantErr       bool
+	}{
+		{name: "string_zero", responseJSON: `{"error": {"code": "0"}}`, wantCode: "0", wantErrorType: "", wantErr: false},
+		{name: "numeric_zero", responseJSON: `{"error": {"code": 0}}`, wantCode: "0", wantErrorType: "", wantErr: false},
+		{name: "empty", responseJSON: `{"error": {}}`, wantCode: "", wantErrorType: "", wantErr: false},
+		{name: "no_error", responseJSON: `{"num_records": 0}`, wantCode: "", wantErrorType: "", wantErr: false},
+		{name: "string_code", responseJSON: `{"error": {"code": "262179", "message": "bad input"}}`, wantCode: "262179", wantErrorType: ErrorTypeRESTError, wantErr: true},
+		{name: "numeric_code", responseJSON: `{"error": {"code": 262179, "message": "bad input"}}`, wantCode: "262179", wantErrorType: ErrorTypeRESTError, wantErr: true},
+		{name: "numeric_zero_with_message", responseJSON: `{"error": {"code": 0, "message": "failed"}}`, wantCode: "0", wantErrorType: ErrorTypeRESTError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &RestClient{
+				ctx: context.Background(),
+			}
+			_, got, err := c.unmarshalResponse(200, []byte(tt.responseJSON), nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RestClient.unmarshalResponse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.RestError.Code != tt.wantCode {
+				t.Errorf("RestClient.unmarshalResponse() code = %q, want %q", got.RestError.Code, tt.wantCode)
+			}
+			if got.ErrorType != tt.wantErrorType {
+				t.Errorf("RestClient.unmarshalResponse() ErrorType = %q, want %q", got.ErrorType, tt.wantErrorType)
+			}
+		})
+	}
+}
+
+func TestErrorType_values(t *testing.T) {
+	// the values are part of logs and may be compared by callers, they must not change
+	want := map[ErrorType]string{
+		ErrorTypeHTTP:            "http",
+		ErrorTypeDecompress:      "bad_response_decompress",
+		ErrorTypeDecodeJSON:      "bad_response_decode_json",
+		ErrorTypeDecodeInterface: "bad_response_decode_interface",
+		ErrorTypeDecodeRaw:       "bad_response_decode_raw",
+		ErrorTypeRESTError:       "rest_error",
+		ErrorTypeStatusCode:      "statuscode_error",
+		ErrorTypeNonJSON:         "non_json_response",
+	}
+	for errorType, value := range want {
+		if string(errorType) != value {
+			t.Errorf("ErrorType = %q, want %q", errorType, value)
+		}
+	}
+}
+
+func TestRestClient_unmarshalResponse_singleObject(t *testing.T) {
+	tests := []struct {
+		name         string
+		responseJSON string
+		wantRecords  []map[string]any
+	}{
+		{name: "single_field", responseJSON: `{"name": "demo"}`, wantRecords: []map[string]any{{"name": "demo"}}},
+		{name: "single_field_with_links", responseJSON: `{"name": "demo", "_links": {"self": {"href": "/api/v1/form"}}}`,
+			wantRecords: []map[string]any{{"name": "demo", "_links": map[string]any{"self": map[string]any{"href": "/api/v1/form"}}}}},
+		{name: "envelope", responseJSON: `{"status": "success", "message": "", "data": {"id": 1}}`,
+			wantRecords: []map[string]any{{"status": "success", "message": "", "data": map[string]any{"id": float64(1)}}}},
+		{name: "links_only", responseJSON: `{"_links": {"self": {"href": "/api/v1/form"}}}`, wantRecords: []map[string]any{}},
+		{name: "empty_record_list", responseJSON: `{"num_records": 0, "records": []}`, wantRecords: []map[string]any{}},
+		{name: "empty_record_list_with_links", responseJSON: `{"records": [], "_links": {"self": {"href": "/api/v1/job"}}}`, wantRecords: []map[string]any{}},
+		{name: "record_list", responseJSON: `{"num_records": 2, "records": [{"id": 1}, {"id": 2}], "_links": {"self": {"href": "/api/v1/job"}}}`,
+			wantRecords: []map[string]any{{"id": float64(1)}, {"id": float64(2)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &RestClient{
+				ctx: context.Background(),
+			}
+			_, got, err := c.unmarshalResponse(200, []byte(tt.responseJSON), nil)
+			if err != nil {
+				t.Fatalf("RestClient.unmarshalResponse() error = %v", err)
+			}
+			if got.NumRecords != len(tt.wantRecords) {
+				t.Errorf("RestClient.unmarshalResponse() NumRecords = %d, want %d", got.NumRecords, len(tt.wantRecords))
+			}
+			if !reflect.DeepEqual(got.Records, tt.wantRecords) && len(got.Records)+len(tt.wantRecords) != 0 {
+				t.Errorf("RestClient.unmarshalResponse() Records = %#v, want %#v", got.Records, tt.wantRecords)
+			}
+		})
+	}
+}
+
+func TestRestClient_decodeResponse_nonJSON(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentType   string
+		body          string
+		wantErrorType ErrorType
+	}{
+		{name: "html", contentType: "text/html; charset=utf-8", body: "<html><title>Welcome to nginx</title></html>", wantErrorType: ErrorTypeNonJSON},
+		{name: "json", contentType: "application/json; charset=utf-8", body: `{"status": "success", "data": {"id": 1}}`},
+		{name: "problem_json", contentType: "application/problem+json", body: `{"status": "success", "data": {"id": 1}}`},
+		{name: "mislabelled_json", contentType: "text/plain", body: `{"status": "success", "data": {"id": 1}}`},
+		{name: "no_content_type", body: "<html></html>", wantErrorType: ErrorTypeDecodeJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &RestClient{
+				ctx: context.Background(),
+			}
+			headers := http.Header{}
+			if tt.contentType != "" {
+				headers.Set("Content-Type", tt.contentType)
+			}
+			_, response, err := c.decodeResponse(http.StatusOK, headers, []byte(tt.body), nil)
+			if (err != nil) != (tt.wantErrorType != "") || response.ErrorType != tt.wantErrorType {
+				t.Fatalf("RestClient.decodeResponse() ErrorType = %q, error = %v, want %q", response.ErrorType, err, tt.wantErrorType)
+			}
+			if tt.wantErrorType == ErrorTypeNonJSON && (!strings.Contains(err.Error(), tt.contentType) || !strings.Contains(err.Error(), "Welcome to nginx")) {
+				t.Errorf("RestClient.decodeResponse() error = %v, want the Content-Type and the start of the body", err)
+			}
+		})
+	}
+}
+
+func TestRestClient_unmarshalResponse_errorBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		statusCode    int
+		responseJSON  string
+		wantRestError RestError
+		wantErrorType ErrorType
+	}{
+		{name: "structured_error", statusCode: 409, responseJSON: `{"error": {"code": 409, "message": "credential ontap already exists", "target": "name"}}`,
+			wantRestError: RestError{Code: "409", Message: "credential ontap already exists", Target: "name"}, wantErrorType: ErrorTypeRESTError},
+		{name: "error_string", statusCode: 409, responseJSON: `{"error": "credential ontap already exists"}`,
+			wantRestError: RestError{Message: "credential ontap already exists"}, wantErrorType: ErrorTypeRESTError},
+		{name: "envelope", statusCode: 400, responseJSON: `{"status": "error", "message": "failed to create credential", "data": {"error": "name is required"}}`,
+			wantRestError: RestError{Message: "failed to create credential: name is required"}, wantErrorType: ErrorTypeRESTError},
+		{name: "envelope_without_detail", statusCode: 400, responseJSON: `{"status": "error", "message": "failed to create credential", "data": {}}`,
+			wantRestError: RestError{Message: "failed to create credential"}, wantErrorType: ErrorTypeRESTError},
+		{name: "not_json", statusCode: 502, responseJSON: `<html>Bad Gateway</html>`, wantErrorType: ErrorTypeStatusCode},
+		{name: "no_details", statusCode: 500, responseJSON: `{"status": "error"}`, wantErrorType: ErrorTypeStatusCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &RestClient{
+				ctx: context.Background(),
+			}
+			got, got1, err := c.unmarshalResponse(tt.statusCode, []byte(tt.responseJSON), nil)
+			if err == nil {
+				t.Fatalf("RestClient.unmarshalResponse() expected an error")
+			}
+			if got != tt.statusCode || got1.StatusCode != tt.statusCode {
+				t.Errorf("RestClient.unmarshalResponse() statusCode = %d, %d, want %d", got, got1.StatusCode, tt.statusCode)
+			}
+			if got1.RestError != tt.wantRestError {
+				t.Errorf("RestClient.unmarshalResponse() RestError = %#v, want %#v", got1.RestError, tt.wantRestError)
+			}
+			if got1.ErrorType != tt.wantErrorType {
+				t.Errorf("RestClient.unmarshalResponse() ErrorType = %q, want %q", got1.ErrorType, tt.wantErrorType)
+			}
+			var restClientError *RestClientError
+			if !errors.As(err, &restClientError) {
+				t.Fatalf("RestClient.unmarshalResponse() error = %#v, want a *RestClientError", err)
+			}
+			if restClientError.StatusCode != tt.statusCode || restClientError.ErrorType != tt.wantErrorType || restClientError.RestError != tt.wantRestError {
+				t.Errorf("RestClient.unmarshalResponse() error = %#v, want statusCode %d, ErrorType %q, RestError %#v", restClientError, tt.statusCode, tt.wantErrorType, tt.wantRestError)
+			}
+		})
+	}
+}
+
+func TestRestClient_unmarshalResponse_logsRedactedBody(t *testing.T) {
+	var output bytes.Buffer
+	c := &RestClient{ctx: tflogtest.RootLogger(context.Background(), &output)}
+	body := `password=s3cr3t&user=admin ` + strings.Repeat("x", 2*maxBodyInError)
+	if _, _, err := c.unmarshalResponse(200, []byte(body), nil); err == nil {
+		t.Fatalf("RestClient.unmarshalResponse() expected an error")
+	}
+	logged := output.String()
+	if strings.Contains(logged, "s3cr3t") || !strings.Contains(logged, "more bytes") {
+		t.Errorf("RestClient.unmarshalResponse() logged %s, want the body redacted and truncated", logged)
+	}
+}
+
+func TestRestClientError(t *testing.T) {
+	c := &RestClient{ctx: context.Background()}
+	tests := []struct {
+		name          string
+		statusCode    int
+		responseJSON  string
+		httpClientErr error
+		wantErrorType ErrorType
+	}{
+		{name: "not_found", statusCode: 404, responseJSON: `{}`, wantErrorType: ErrorTypeStatusCode},
+		{name: "not_json", statusCode: 200, responseJSON: `<html>`, wantErrorType: ErrorTypeDecodeJSON},
+		{name: "http", statusCode: -1, httpClientErr: context.Canceled, wantErrorType: ErrorTypeHTTP},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := c.unmarshalResponse(tt.statusCode, []byte(tt.responseJSON), tt.httpClientErr)
+			var restClientError *RestClientError
+			if !errors.As(err, &restClientError) {
+				t.Fatalf("unmarshalResponse() error = %#v, want a *RestClientError", err)
+			}
+			if restClientError.StatusCode != tt.statusCode || restClientError.ErrorType != tt.wantErrorType {
+				t.Errorf("unmarshalResponse() error = %#v, want statusCode %d, ErrorType %q", restClientError, tt.statusCode, tt.wantErrorType)
+			}
+			if restClientError.Error() == "" || restClientError.Error() != restClientError.Unwrap().Error() {
+				t.Errorf("Error() = %q, want the message of the underlying error", restClientError.Error())
+			}
+			if tt.httpClientErr != nil && !errors.Is(err, tt.httpClientErr) {
+				t.Errorf("unmarshalResponse() error = %v, want it to wrap %v", err, tt.httpClientErr)
+			}
+		})
+	}
+	if _, _, err := c.unmarshalResponse(200, []byte(`{"status": "success"}`), nil); err != nil {
+		t.Errorf("unmarshalResponse() error = %#v, want nil", err)
+	}
+}
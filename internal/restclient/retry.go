@@ -0,0 +1,153 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// RetryPolicy describes how failed requests are retried.
+// MaxRetries is the number of retries after the first attempt, 0 disables retries.
+// The wait before retry n (starting at 0) is WaitMin * 2^n, capped to WaitMax, with jitter,
+// unless the server asks for a specific wait with a Retry-After header.
+type RetryPolicy struct {
+	MaxRetries int
+	WaitMin    time.Duration
+	WaitMax    time.Duration
+}
+
+// SetRetryPolicy enables retries for connection errors and transient status codes.
+func (r *RestClient) SetRetryPolicy(policy RetryPolicy) {
+	r.retryPolicy = policy
+}
+
+// isConnectionError reports whether err was raised while reaching the server, rather than while building the request.
+func isConnectionError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// IsTransientError reports whether err is a failure that may go away on its own: the server could not be reached,
+// or it answered with a 5xx status code. An aborted request is not transient.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var restErr *RestClientError
+	if errors.As(err, &restErr) && restErr.StatusCode >= http.StatusInternalServerError {
+		return true
+	}
+	return isConnectionError(err)
+}
+
+// isDialError reports whether err was raised before the request was sent: the host could not be resolved,
+// or the connection to the server or the proxy could not be opened.
+func isDialError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && (opErr.Op == "dial" || opErr.Op == "proxyconnect")
+}
+
+// isIdempotent reports whether sending a request with method twice has the same effect as sending it once.
+func isIdempotent(method string) bool {
+	return method != http.MethodPost && method != http.MethodPatch
+}
+
+// shouldRetry reports whether a request can be retried.
+// A POST may launch a job, so it is only retried when the connection could not be opened, as the request was then never sent.
+// It is not retried on a timeout or a reset connection, nor on a received status code.
+func shouldRetry(method string, statusCode int, httpClientErr error) bool {
+	if httpClientErr != nil {
+		if !isIdempotent(method) {
+			return isDialError(httpClientErr)
+		}
+		return isConnectionError(httpClientErr)
+	}
+	if !isIdempotent(method) {
+		return false
+	}
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the time to wait before retry attempt (starting at 0), using exponential backoff and jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.WaitMin
+	for i := 0; i < attempt && wait < p.WaitMax; i++ {
+		wait *= 2
+	}
+	if wait > p.WaitMax {
+		wait = p.WaitMax
+	}
+	if wait <= 0 {
+		return 0
+	}
+	// full wait in the upper half, so that concurrent clients do not retry in lockstep.
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+// retryWait returns the wait before retry attempt (starting at 0) of a request: the Retry-After of the response when set, else the backoff.
+// Retry-After is capped to WaitMax when set, and to the time left before the deadline of the client context,
+// so that a server cannot hold the provider for longer than configured.
+func (r *RestClient) retryWait(method string, baseURL string, attempt int, statusCode int, headers http.Header) time.Duration {
+	now := time.Now()
+	requested, ok := retryAfter(statusCode, headers, now)
+	if !ok {
+		return r.retryPolicy.backoff(attempt)
+	}
+	wait, limit := requested, "retry_wait_max"
+	if r.retryPolicy.WaitMax > 0 && wait > r.retryPolicy.WaitMax {
+		wait = r.retryPolicy.WaitMax
+	}
+	if deadline, ok := r.ctx.Deadline(); ok && deadline.Sub(now) < wait {
+		wait, limit = max(deadline.Sub(now), 0), "the operation deadline"
+	}
+	if wait < requested {
+		tflog.Info(r.ctx, fmt.Sprintf("%s %s: Retry-After of %s exceeds %s, waiting %s", method, baseURL, requested, limit, wait))
+	}
+
+	return wait
+}
+
+// retryAfter returns the wait requested by the server with a Retry-After header on a 429 or 503 response.
+// Both delta-seconds and HTTP-date forms are supported.  ok is false when there is no usable header,
+// in which case the computed backoff applies.
+func retryAfter(statusCode int, headers http.Header, now time.Time) (wait time.Duration, ok bool) {
+	if statusCode != http.StatusTooManyRequests && statusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := strings.TrimSpace(headers.Get("Retry-After"))
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait = date.Sub(now); wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
@@ -0,0 +1,235 @@
+package restclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	connectionErr := &url.Error{Op: "Get", URL: "https://host/api", Err: errors.New("connection refused")}
+	dialErr := &url.Error{Op: "Post", URL: "https://host/api", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	dnsErr := &url.Error{Op: "Post", URL: "https://host/api", Err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "host"}}}
+	resetErr := &url.Error{Op: "Post", URL: "https://host/api", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}}
+	timeoutErr := &url.Error{Op: "Post", URL: "https://host/api", Err: context.DeadlineExceeded}
+	tests := []struct {
+		name          string
+		method        string
+		statusCode    int
+		httpClientErr error
+		want          bool
+	}{
+		{name: "get_ok", method: "GET", statusCode: 200, want: false},
+		{name: "get_404", method: "GET", statusCode: 404, want: false},
+		{name: "get_500", method: "GET", statusCode: 500, want: false},
+		{name: "get_429", method: "GET", statusCode: 429, want: true},
+		{name: "get_502", method: "GET", statusCode: 502, want: true},
+		{name: "get_503", method: "GET", statusCode: 503, want: true},
+		{name: "get_504", method: "GET", statusCode: 504, want: true},
+		{name: "delete_503", method: "DELETE", statusCode: 503, want: true},
+		{name: "post_503", method: "POST", statusCode: 503, want: false},
+		{name: "post_429", method: "POST", statusCode: 429, want: false},
+		{name: "get_connection_error", method: "GET", statusCode: -1, httpClientErr: connectionErr, want: true},
+		{name: "post_dial_error", method: "POST", statusCode: -1, httpClientErr: dialErr, want: true},
+		{name: "post_dns_error", method: "POST", statusCode: -1, httpClientErr: dnsErr, want: true},
+		{name: "post_connection_reset", method: "POST", statusCode: -1, httpClientErr: resetErr, want: false},
+		{name: "post_timeout", method: "POST", statusCode: -1, httpClientErr: timeoutErr, want: false},
+		{name: "patch_connection_reset", method: "PATCH", statusCode: -1, httpClientErr: resetErr, want: false},
+		{name: "patch_503", method: "PATCH", statusCode: 503, want: false},
+		{name: "get_connection_reset", method: "GET", statusCode: -1, httpClientErr: resetErr, want: true},
+		{name: "get_build_error", method: "GET", statusCode: -1, httpClientErr: errors.New("bad request"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRetry(tt.method, tt.statusCode, tt.httpClientErr); got != tt.want {
+				t.Errorf("shouldRetry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	connectionErr := &url.Error{Op: "Get", URL: "https://host/api", Err: errors.New("connection refused")}
+	canceledErr := &url.Error{Op: "Get", URL: "https://host/api", Err: context.Canceled}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "connection_error", err: &RestClientError{StatusCode: -1, ErrorType: ErrorTypeHTTP, err: connectionErr}, want: true},
+		{name: "canceled", err: &RestClientError{StatusCode: -1, ErrorType: ErrorTypeHTTP, err: canceledErr}, want: false},
+		{name: "status_500", err: &RestClientError{StatusCode: 500, ErrorType: ErrorTypeStatusCode, err: errors.New("500")}, want: true},
+		{name: "status_503", err: &RestClientError{StatusCode: 503, ErrorType: ErrorTypeStatusCode, err: errors.New("503")}, want: true},
+		{name: "status_404", err: &RestClientError{StatusCode: 404, ErrorType: ErrorTypeStatusCode, err: errors.New("404")}, want: false},
+		{name: "decode_error", err: &RestClientError{StatusCode: 200, ErrorType: ErrorTypeDecodeJSON, err: errors.New("bad json")}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransientError(tt.err); got != tt.want {
+				t.Errorf("IsTransientError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 10, WaitMin: time.Second, WaitMax: 10 * time.Second}
+	tests := []struct {
+		attempt int
+		wantMax time.Duration
+	}{
+		{attempt: 0, wantMax: time.Second},
+		{attempt: 1, wantMax: 2 * time.Second},
+		{attempt: 2, wantMax: 4 * time.Second},
+		{attempt: 3, wantMax: 8 * time.Second},
+		{attempt: 4, wantMax: 10 * time.Second},
+		{attempt: 9, wantMax: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			got := policy.backoff(tt.attempt)
+			if got < tt.wantMax/2 || got > tt.wantMax {
+				t.Errorf("backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.wantMax/2, tt.wantMax)
+			}
+		}
+	}
+}
+
+func TestRestClient_retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		failures  int32
+		wantCalls int32
+		wantErr   bool
+	}{
+		{name: "get_recovers", method: "GET", failures: 2, wantCalls: 3, wantErr: false},
+		{name: "get_exhausts_retries", method: "GET", failures: 5, wantCalls: 4, wantErr: true},
+		{name: "post_not_retried", method: "POST", failures: 1, wantCalls: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) <= tt.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				_, _ = w.Write([]byte(`{"status": "success", "message": "ok"}`))
+			}))
+			defer server.Close()
+			cxProfile := ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+			client, err := NewClient(context.Background(), cxProfile, "resource/version", 600)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, WaitMin: time.Millisecond, WaitMax: 5 * time.Millisecond})
+
+			_, _, err = client.callAPIMethod(tt.method, "job", nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("callAPIMethod() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("callAPIMethod() made %d calls, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRestClient_retryCanceled(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cxProfile := ConnectionProfile{Hostname: strings.TrimPrefix(server.URL, "https://"), Token: "token"}
+	client, err := NewClient(ctx, cxProfile, "resource/version", 600)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	client.SetRetryPolicy(RetryPolicy{MaxRetries: 3, WaitMin: time.Minute, WaitMax: time.Minute})
+
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	_, _, err = client.callAPIMethod("GET", "job", nil, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("callAPIMethod() error = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("callAPIMethod() returned after %s, want prompt return on cancel", elapsed)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 6, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		statusCode int
+		value      string
+		want       time.Duration
+		wantOk     bool
+	}{
+		{name: "delta_seconds", statusCode: 429, value: "120", want: 120 * time.Second, wantOk: true},
+		{name: "delta_seconds_zero", statusCode: 429, value: "0", want: 0, wantOk: true},
+		{name: "http_date", statusCode: 429, value: "Mon, 06 May 2024 12:00:30 GMT", want: 30 * time.Second, wantOk: true},
+		{name: "http_date_in_the_past", statusCode: 429, value: "Mon, 06 May 2024 11:00:00 GMT", want: 0, wantOk: true},
+		{name: "service_unavailable", statusCode: 503, value: "5", want: 5 * time.Second, wantOk: true},
+		{name: "missing", statusCode: 429, value: "", want: 0, wantOk: false},
+		{name: "malformed", statusCode: 429, value: "soon", want: 0, wantOk: false},
+		{name: "negative", statusCode: 429, value: "-3", want: 0, wantOk: false},
+		{name: "ignored_on_502", statusCode: 502, value: "5", want: 0, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			if tt.value != "" {
+				headers.Set("Retry-After", tt.value)
+			}
+			got, gotOk := retryAfter(tt.statusCode, headers, now)
+			if got != tt.want || gotOk != tt.wantOk {
+				t.Errorf("retryAfter() = %s, %v, want %s, %v", got, gotOk, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestRestClient_retryWait(t *testing.T) {
+	headers := http.Header{"Retry-After": []string{"3600"}}
+	tests := []struct {
+		name     string
+		waitMax  time.Duration
+		deadline time.Duration
+		want     time.Duration
+	}{
+		{name: "no_cap", want: time.Hour},
+		{name: "retry_wait_max", waitMax: 30 * time.Second, want: 30 * time.Second},
+		{name: "deadline", waitMax: 30 * time.Minute, deadline: time.Minute, want: time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewMockedRestClient(nil)
+			if err != nil {
+				panic(err)
+			}
+			r.SetRetryPolicy(RetryPolicy{MaxRetries: 1, WaitMax: tt.waitMax})
+			if tt.deadline > 0 {
+				ctx, cancel := context.WithTimeout(context.Background(), tt.deadline)
+				defer cancel()
+				r.ctx = ctx
+			}
+			got := r.retryWait("GET", "job", 0, 429, headers)
+			// the time left before the deadline decreases while the test runs
+			if got > tt.want || got < tt.want-time.Second {
+				t.Errorf("retryWait() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
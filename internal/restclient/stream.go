@@ -0,0 +1,125 @@
+package restclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+
+	"terraform-provider-ansible-forms/internal/restclient/httpclient"
+)
+
+// GetStream sends a GET request, and passes the response body to consume as it is received, with its Content-Type,
+// so that a large response such as a job output is not read in memory.  The body is decompressed when needed.
+// As with callAPIMethod, the client logs in again once on a 401, and retries the request per the retry policy,
+// but only as long as consume was not called: the request is not sent again once part of the body was consumed.
+// An error status code is reported as a RestClientError, as with the other methods.
+func (r *RestClient) GetStream(baseURL string, query *RestQuery, consume func(contentType string, body io.Reader) error) (int, error) {
+	if r.mode == "mock" {
+		return r.mockGetStream(baseURL, query, consume)
+	}
+	values := url.Values{}
+	if query != nil {
+		values = query.Values
+	}
+
+	tflog.Debug(r.ctx, fmt.Sprintf("calling GET %s, streaming the response", baseURL))
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		consumed := false
+		if err := r.waitForAvailableSlot(); err != nil {
+			statusCode, _, err := r.unmarshalResponse(-1, nil, err)
+			return statusCode, err
+		}
+		start := time.Now()
+		statusCode, response, headers, httpClientErr := r.httpClient.DoStream(baseURL, &httpclient.Request{
+			Method:  "GET",
+			Query:   values,
+			Headers: r.headers,
+		}, func(headers http.Header, body io.Reader) error {
+			consumed = true
+			reader, err := decompressReader(headers, body)
+			if err != nil {
+				return err
+			}
+			defer reader.Close()
+
+			return consume(headers.Get("Content-Type"), reader)
+		})
+		r.logTiming("GET", baseURL, attempt, statusCode, time.Since(start))
+		r.releaseSlot()
+
+		if consumed {
+			r.captureResponseHeaders(headers)
+			if httpClientErr != nil {
+				return statusCode, &RestClientError{StatusCode: statusCode, ErrorType: ErrorTypeStream,
+					err: fmt.Errorf("unable to read the response of GET %s: %w", baseURL, httpClientErr)}
+			}
+			return statusCode, nil
+		}
+		// the session may expire during a long apply, log in again once and send the request again
+		if statusCode == http.StatusUnauthorized && httpClientErr == nil && !reauthenticated {
+			reauthenticated = true
+			tflog.Info(r.ctx, fmt.Sprintf("GET %s returned statusCode 401, logging in again", baseURL))
+			if err := r.httpClient.Reauthenticate(); err != nil {
+				r.captureResponseHeaders(headers)
+				statusCode, _, err = r.sessionExpired(statusCode, headers, response, err)
+				return statusCode, err
+			}
+			// the request sent after logging in again is not counted as a retry
+			attempt--
+			continue
+		}
+		if attempt >= r.retryPolicy.MaxRetries || !shouldRetry("GET", statusCode, httpClientErr) {
+			r.captureResponseHeaders(headers)
+			return r.streamError(baseURL, statusCode, headers, response, httpClientErr)
+		}
+		wait := r.retryWait("GET", baseURL, attempt, statusCode, headers)
+		tflog.Debug(r.ctx, fmt.Sprintf("retrying GET %s in %s, attempt %d of %d - statusCode %d, err %v", baseURL, wait, attempt+1, r.retryPolicy.MaxRetries, statusCode, httpClientErr))
+		select {
+		case <-r.ctx.Done():
+			_, _, err := r.unmarshalResponse(statusCode, nil, r.ctx.Err())
+			return statusCode, err
+		case <-time.After(wait):
+		}
+	}
+}
+
+// streamError decodes the response of a streamed request that was not consumed, i.e. that failed, to report its error.
+func (r *RestClient) streamError(baseURL string, statusCode int, headers http.Header, responseJSON []byte, httpClientErr error) (int, error) {
+	statusCode, response, err := r.decodeResponse(statusCode, headers, responseJSON, httpClientErr)
+	if err == nil {
+		response.ErrorType = ErrorTypeStatusCode
+		err = newRestClientError(response, fmt.Errorf("unexpected response to GET %s, statusCode %d", baseURL, statusCode))
+	}
+
+	return statusCode, err
+}
+
+// mockGetStream returns the next mocked response, and passes its first record to consume as a JSON body when it has no error,
+// in the envelope of the API: {"status": "success", "data": record}.
+func (r *RestClient) mockGetStream(baseURL string, query *RestQuery, consume func(contentType string, body io.Reader) error) (int, error) {
+	statusCode, response, err := r.mockCallAPIMethod("GET", baseURL, query, nil)
+	if err != nil {
+		return statusCode, err
+	}
+	envelope := map[string]any{"status": "success", "data": map[string]any{}}
+	if len(response.Records) > 0 {
+		envelope["data"] = response.Records[0]
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return statusCode, err
+	}
+	if err := consume("application/json", bytes.NewReader(body)); err != nil {
+		return statusCode, &RestClientError{StatusCode: statusCode, ErrorType: ErrorTypeStream,
+			err: fmt.Errorf("unable to read the response of GET %s: %w", baseURL, err)}
+	}
+
+	return statusCode, nil
+}
@@ -46,6 +46,13 @@ func (e *ErrorHandler) MakeAndReportError(summary string, msg string) error {
 	return errors.New(fullMsg)
 }
 
+// ReportWarning logs msg with tflog, and adds a warning to the diagnostic, reported by Terraform
+func (e *ErrorHandler) ReportWarning(summary string, msg string) {
+	e.validate()
+	tflog.SubsystemWarn(e.subCtx, e.name, msg)
+	e.diags.AddWarning(summary, msg)
+}
+
 func (e *ErrorHandler) validate() {
 	if e == nil {
 		panic("Error handler is not set")